@@ -0,0 +1,39 @@
+// Command openapi writes the OpenAPI spec of the documented routes.
+//
+//	go run ./cmd/openapi -o openapi.yaml
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/BoomNooB/medium-go-di/handler"
+	"github.com/BoomNooB/medium-go-di/openapi"
+)
+
+func main() {
+	out := flag.String("o", "openapi.yaml", "file to write the spec to, - for stdout")
+	flag.Parse()
+
+	if err := run(*out); err != nil {
+		fmt.Fprintln(os.Stderr, "openapi:", err)
+		os.Exit(1)
+	}
+}
+
+func run(out string) error {
+	doc, err := openapi.Generate(handler.Routes)
+	if err != nil {
+		return err
+	}
+	b, err := openapi.MarshalYAML(doc)
+	if err != nil {
+		return err
+	}
+	if out == "-" {
+		_, err = os.Stdout.Write(b)
+		return err
+	}
+	return os.WriteFile(out, b, 0644)
+}
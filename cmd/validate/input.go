@@ -0,0 +1,231 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/BoomNooB/medium-go-di/jsontag"
+)
+
+const (
+	inputJSON  = "json"
+	inputJSONL = "jsonl"
+	inputCSV   = "csv"
+)
+
+// maxLineSize bounds the memory used by a single JSON Lines record.
+const maxLineSize = 1 << 20
+
+// rawRecord is one payload read from the input, as JSON. Pos is the line of
+// the record for JSON Lines and CSV, and its index for JSON.
+type rawRecord struct {
+	Pos  int
+	JSON []byte
+	Err  error
+}
+
+// inputFormat picks the format from the flag, or from the file extension.
+func inputFormat(flagValue, path string) (string, error) {
+	if flagValue != "" {
+		switch flagValue {
+		case inputJSON, inputJSONL, inputCSV:
+			return flagValue, nil
+		}
+		return "", fmt.Errorf("unknown input format %q", flagValue)
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return inputJSON, nil
+	case ".jsonl", ".ndjson":
+		return inputJSONL, nil
+	case ".csv":
+		return inputCSV, nil
+	}
+	return "", fmt.Errorf("cannot guess the format of %q, use -in", path)
+}
+
+// readRecords calls fn for every record of r. reqType is the request struct,
+// CSV columns are typed after its json fields.
+func readRecords(format string, r io.Reader, reqType reflect.Type, fn func(rawRecord)) error {
+	switch format {
+	case inputJSON:
+		return readJSON(r, fn)
+	case inputJSONL:
+		return readJSONLines(r, fn)
+	case inputCSV:
+		return readCSV(r, reqType, fn)
+	}
+	return fmt.Errorf("unknown input format %q", format)
+}
+
+// readJSON accepts a single object, an array of objects or a stream of
+// concatenated objects.
+func readJSON(r io.Reader, fn func(rawRecord)) error {
+	br := bufio.NewReader(r)
+	dec := json.NewDecoder(br)
+
+	first, err := firstNonSpace(br)
+	if err != nil {
+		return err
+	}
+	if first == '[' {
+		if _, err := dec.Token(); err != nil {
+			return err
+		}
+	}
+
+	for pos := 1; dec.More(); pos++ {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			// the decoder cannot resync after a syntax error
+			fn(rawRecord{Pos: pos, Err: err})
+			return nil
+		}
+		fn(rawRecord{Pos: pos, JSON: raw})
+	}
+	return nil
+}
+
+func firstNonSpace(br *bufio.Reader) (byte, error) {
+	for {
+		b, err := br.Peek(1)
+		if errors.Is(err, io.EOF) {
+			return 0, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		switch b[0] {
+		case ' ', '\t', '\r', '\n':
+			br.ReadByte()
+		default:
+			return b[0], nil
+		}
+	}
+}
+
+func readJSONLines(r io.Reader, fn func(rawRecord)) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	line := 0
+	for scanner.Scan() {
+		line++
+		b := bytes.TrimSpace(scanner.Bytes())
+		if len(b) == 0 {
+			continue
+		}
+		fn(rawRecord{Pos: line, JSON: bytes.Clone(b)})
+	}
+	return scanner.Err()
+}
+
+// readCSV turns every row into a JSON object keyed by the header. Columns of
+// number and bool fields are written as JSON literals, empty cells are left
+// out so required rules still apply.
+func readCSV(r io.Reader, reqType reflect.Type, fn func(rawRecord)) error {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	kinds := jsonFieldKinds(reqType)
+
+	for {
+		row, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		line, _ := cr.FieldPos(0)
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				fn(rawRecord{Pos: parseErr.StartLine, Err: err})
+				continue
+			}
+			return err
+		}
+
+		obj := make(map[string]json.RawMessage, len(header))
+		var colErr error
+		for i, name := range header {
+			if i >= len(row) || row[i] == "" {
+				continue
+			}
+			kind := kinds[name]
+			if !isLiteralKind(kind) {
+				b, _ := json.Marshal(row[i])
+				obj[name] = b
+				continue
+			}
+			if !matchesKind(row[i], kind) {
+				colErr = fmt.Errorf("column %s: %q is not a %s", name, row[i], kind)
+			}
+			obj[name] = json.RawMessage(row[i])
+		}
+		if colErr != nil {
+			fn(rawRecord{Pos: line, Err: colErr})
+			continue
+		}
+		b, err := json.Marshal(obj)
+		if err != nil {
+			return err
+		}
+		fn(rawRecord{Pos: line, JSON: b})
+	}
+}
+
+// isLiteralKind reports whether columns of kind are written as JSON
+// literals rather than strings.
+func isLiteralKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64, reflect.Bool:
+		return true
+	}
+	return false
+}
+
+// matchesKind reports whether the column value v can be decoded into a
+// field of kind, so a mismatch is reported as a column error.
+func matchesKind(v string, kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Bool:
+		return v == "true" || v == "false"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		_, err := strconv.ParseInt(v, 10, 64)
+		return err == nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		_, err := strconv.ParseUint(v, 10, 64)
+		return err == nil
+	default:
+		// ParseFloat also takes NaN, Inf and hex floats, JSON does not
+		_, err := strconv.ParseFloat(v, 64)
+		return err == nil && json.Valid([]byte(v))
+	}
+}
+
+// jsonFieldKinds maps the json name of every field of t to its kind.
+func jsonFieldKinds(t reflect.Type) map[string]reflect.Kind {
+	kinds := map[string]reflect.Kind{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if name := jsontag.Name(f); name != "" {
+			kinds[name] = f.Type.Kind()
+		}
+	}
+	return kinds
+}
@@ -0,0 +1,216 @@
+// Command validate checks a file of payloads against the rules of the
+// handler request structs, without running the server.
+//
+//	go run ./cmd/validate [-in json|jsonl|csv] [-o text|json] <kind> <file>
+//
+// kind is one of favorite, pet-name, thai-cid or guess-cat and file may be
+// "-" for stdin, in which case -in is required. Records are numbered by line
+// for JSON Lines and CSV, and by position for JSON. The exit code is 1 when a
+// record is invalid and 2 when the input cannot be read.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/BoomNooB/medium-go-di/handler"
+	"github.com/BoomNooB/medium-go-di/jsontag"
+	"github.com/BoomNooB/medium-go-di/redact"
+	"github.com/BoomNooB/medium-go-di/validatorwrapper"
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	exitOK      = 0
+	exitInvalid = 1
+	exitError   = 2
+
+	outputText = "text"
+	outputJSON = "json"
+)
+
+// RecordResult is the report of a single record.
+type RecordResult struct {
+	Record int                           `json:"record"`
+	Valid  bool                          `json:"valid"`
+	Error  string                        `json:"error,omitempty"`
+	Errors []validatorwrapper.FieldError `json:"errors,omitempty"`
+}
+
+// Report is the whole output of a run.
+type Report struct {
+	Kind    string         `json:"kind"`
+	Total   int            `json:"total"`
+	Invalid int            `json:"invalid"`
+	Records []RecordResult `json:"records"`
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("validate", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	in := fs.String("in", "", "input format: json, jsonl or csv (default: from the file extension)")
+	out := fs.String("o", outputText, "output format: text or json")
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "usage: validate [flags] <%s> <file>\n", strings.Join(handler.RequestKinds(), "|"))
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+	if fs.NArg() != 2 || (*out != outputText && *out != outputJSON) {
+		fs.Usage()
+		return exitError
+	}
+	kind, path := fs.Arg(0), fs.Arg(1)
+
+	req, ok := handler.NewRequest(kind)
+	if !ok {
+		fmt.Fprintf(stderr, "validate: unknown kind %q\n", kind)
+		return exitError
+	}
+	reqType := reflect.TypeOf(req).Elem()
+
+	format, err := inputFormat(*in, path)
+	if err != nil {
+		fmt.Fprintln(stderr, "validate:", err)
+		return exitError
+	}
+
+	r := stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			fmt.Fprintln(stderr, "validate:", err)
+			return exitError
+		}
+		defer file.Close()
+		r = file
+	}
+
+	// Same validator setup as the server, without recording the errors
+	v := validator.New(validator.WithRequiredStructEnabled())
+	vWrapper := validatorwrapper.NewValidatorWrapper(v, validatorwrapper.NewNopSink(), slog.New(slog.DiscardHandler))
+
+	report := Report{Kind: kind, Records: []RecordResult{}}
+	err = readRecords(format, r, reqType, func(rec rawRecord) {
+		result := validateRecord(vWrapper, kind, reqType, rec)
+		report.Total++
+		if !result.Valid {
+			report.Invalid++
+		}
+		report.Records = append(report.Records, result)
+	})
+	if err != nil {
+		fmt.Fprintln(stderr, "validate:", err)
+		return exitError
+	}
+
+	if *out == outputJSON {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(report)
+	} else {
+		err = writeText(stdout, report)
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, "validate:", err)
+		return exitError
+	}
+
+	if report.Invalid > 0 {
+		return exitInvalid
+	}
+	return exitOK
+}
+
+func validateRecord(v handler.Valiator, kind string, reqType reflect.Type, rec rawRecord) RecordResult {
+	result := RecordResult{Record: rec.Pos}
+	if rec.Err != nil {
+		result.Error = rec.Err.Error()
+		return result
+	}
+
+	req, _ := handler.NewRequest(kind)
+	if err := json.Unmarshal(rec.JSON, req); err != nil {
+		result.Error = "json not valid: " + err.Error()
+		return result
+	}
+
+	err := v.StructValidation(context.Background(), req)
+	if err == nil {
+		result.Valid = true
+		return result
+	}
+	var vErr *validatorwrapper.ValidationError
+	if !errors.As(err, &vErr) {
+		result.Error = err.Error()
+		return result
+	}
+	for _, f := range vErr.Fields {
+		f.Value = redactedValue(reqType, f.Field, f.Value)
+		result.Errors = append(result.Errors, f)
+	}
+	return result
+}
+
+// redactedValue applies the pii tag of the field reported as field, so the
+// report can be kept in CI logs.
+func redactedValue(reqType reflect.Type, field string, value any) any {
+	for i := 0; i < reqType.NumField(); i++ {
+		f := reqType.Field(i)
+		if jsontag.Name(f) == field {
+			return redact.Apply(redact.FieldTag(reqType, []string{f.Name}), value)
+		}
+	}
+	return value
+}
+
+func writeText(w io.Writer, report Report) error {
+	for _, r := range report.Records {
+		var err error
+		switch {
+		case r.Valid:
+			_, err = fmt.Fprintf(w, "record %d: ok\n", r.Record)
+		case r.Error != "":
+			_, err = fmt.Fprintf(w, "record %d: %s\n", r.Record, r.Error)
+		default:
+			_, err = fmt.Fprintf(w, "record %d: invalid\n", r.Record)
+			for _, f := range r.Errors {
+				if err != nil {
+					break
+				}
+				_, err = fmt.Fprintf(w, "  %s: %s\n", f.Field, describeRule(f))
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "%d records, %d invalid\n", report.Total, report.Invalid)
+	return err
+}
+
+// describeRule prints the failed rule like in the validate tag, with the
+// offending value when there is one.
+func describeRule(f validatorwrapper.FieldError) string {
+	rule := f.Tag
+	if f.Param != "" {
+		rule += "=" + f.Param
+	}
+	if f.Value == nil || f.Value == "" {
+		return rule
+	}
+	return fmt.Sprintf("%s (got %v)", rule, f.Value)
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeInput(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func runValidate(t *testing.T, stdin string, args ...string) (int, Report, string) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	code := run(append([]string{"-o", "json"}, args...), strings.NewReader(stdin), &stdout, &stderr)
+
+	var report Report
+	if code != exitError {
+		require.NoError(t, json.Unmarshal(stdout.Bytes(), &report), stderr.String())
+	}
+	return code, report, stderr.String()
+}
+
+func TestRun_JSONLines(t *testing.T) {
+	path := writeInput(t, "payloads.jsonl", strings.Join([]string{
+		`{"citizenId":"1234567890121","fullName":"John Doe"}`,
+		`{"citizenId":"1234567890122","fullName":"John Doe"}`,
+		``,
+		`{"citizenId":`,
+	}, "\n"))
+
+	code, report, _ := runValidate(t, "", "thai-cid", path)
+
+	assert.Equal(t, exitInvalid, code)
+	assert.Equal(t, 3, report.Total)
+	assert.Equal(t, 2, report.Invalid)
+	require.Len(t, report.Records, 3)
+	assert.True(t, report.Records[0].Valid)
+
+	assert.Equal(t, 2, report.Records[1].Record)
+	require.Len(t, report.Records[1].Errors, 1)
+	assert.Equal(t, "thai_cid", report.Records[1].Errors[0].Tag)
+	// pii tagged values are redacted in the report
+	assert.Equal(t, "*********0122", report.Records[1].Errors[0].Value)
+
+	assert.Equal(t, 4, report.Records[2].Record)
+	assert.Contains(t, report.Records[2].Error, "json not valid")
+}
+
+func TestRun_JSONArray(t *testing.T) {
+	path := writeInput(t, "payloads.json", `[
+		{"petName":"Buddy","ownerId":"550e8400-e29b-41d4-a716-446655440000"},
+		{"petName":"Max","ownerId":"550e8400-e29b-41d4-a716-446655440000"}
+	]`)
+
+	code, report, _ := runValidate(t, "", "pet-name", path)
+
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, 2, report.Total)
+	assert.Zero(t, report.Invalid)
+}
+
+func TestRun_CSV(t *testing.T) {
+	path := writeInput(t, "payloads.csv", "userId,favNum\n"+
+		"550e8400-e29b-41d4-a716-446655440000,7\n"+
+		"550e8400-e29b-41d4-a716-446655440000,seven\n"+
+		"550e8400-e29b-41d4-a716-446655440000,\n"+
+		"550e8400-e29b-41d4-a716-446655440000,true\n")
+
+	code, report, _ := runValidate(t, "", "favorite", path)
+
+	assert.Equal(t, exitInvalid, code)
+	require.Len(t, report.Records, 4)
+	assert.True(t, report.Records[0].Valid)
+	assert.Equal(t, 2, report.Records[0].Record, "CSV records are numbered by line")
+	assert.Contains(t, report.Records[1].Error, "favNum")
+	require.Len(t, report.Records[2].Errors, 1)
+	assert.Equal(t, "favNum", report.Records[2].Errors[0].Field)
+	assert.Equal(t, "required", report.Records[2].Errors[0].Tag)
+	assert.Equal(t, `column favNum: "true" is not a int`, report.Records[3].Error)
+}
+
+func TestMatchesKind(t *testing.T) {
+	tests := []struct {
+		value string
+		kind  reflect.Kind
+		want  bool
+	}{
+		{value: "7", kind: reflect.Int, want: true},
+		{value: "-7", kind: reflect.Int64, want: true},
+		{value: "true", kind: reflect.Int, want: false},
+		{value: "7.5", kind: reflect.Int, want: false},
+		{value: "-7", kind: reflect.Uint, want: false},
+		{value: "7.5", kind: reflect.Float64, want: true},
+		{value: "1e3", kind: reflect.Float64, want: true},
+		{value: "NaN", kind: reflect.Float64, want: false},
+		{value: "true", kind: reflect.Bool, want: true},
+		{value: "1", kind: reflect.Bool, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.kind.String()+" "+tt.value, func(t *testing.T) {
+			assert.Equal(t, tt.want, matchesKind(tt.value, tt.kind))
+		})
+	}
+}
+
+func TestRun_Stdin(t *testing.T) {
+	code, report, _ := runValidate(t,
+		`{"guessName":"Fluffy","userId":"550e8400-e29b-41d4-a716-446655440000"}`,
+		"-in", "jsonl", "guess-cat", "-")
+
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, 1, report.Total)
+}
+
+func TestRun_TextOutput(t *testing.T) {
+	path := writeInput(t, "payloads.jsonl", `{"petName":"B","ownerId":"550e8400-e29b-41d4-a716-446655440000"}`)
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"pet-name", path}, nil, &stdout, &stderr)
+
+	assert.Equal(t, exitInvalid, code)
+	assert.Equal(t, "record 1: invalid\n  petName: min=2 (got B)\n1 records, 1 invalid\n", stdout.String())
+}
+
+func TestRun_UsageErrors(t *testing.T) {
+	path := writeInput(t, "payloads.txt", `{}`)
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "missing file", args: []string{"favorite"}},
+		{name: "unknown kind", args: []string{"unknown", path}},
+		{name: "unknown extension", args: []string{"favorite", path}},
+		{name: "stdin without format", args: []string{"favorite", "-"}},
+		{name: "file not found", args: []string{"favorite", filepath.Join(t.TempDir(), "missing.json")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _, stderr := runValidate(t, "", tt.args...)
+			assert.Equal(t, exitError, code)
+			assert.NotEmpty(t, stderr)
+		})
+	}
+}
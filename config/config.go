@@ -0,0 +1,298 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"gopkg.in/yaml.v3"
+)
+
+// Config holds every setting main needs to wire the application.
+// Values come from Default, then the optional file named by CONFIG_FILE,
+// then environment variables.
+type Config struct {
+	Server     ServerConfig     `yaml:"server" validate:"required"`
+	Validation ValidationConfig `yaml:"validation" validate:"required"`
+	Game       GameConfig       `yaml:"game" validate:"required"`
+	Storage    StorageConfig    `yaml:"storage" validate:"required"`
+	Log        LogConfig        `yaml:"log" validate:"required"`
+	Tracing    TracingConfig    `yaml:"tracing" validate:"required"`
+	RateLimit  RateLimitConfig  `yaml:"rateLimit"`
+	Admin      AdminConfig      `yaml:"admin"`
+}
+
+type ServerConfig struct {
+	Addr            string        `yaml:"addr" validate:"required,hostname_port"`
+	ShutdownTimeout time.Duration `yaml:"shutdownTimeout" validate:"gt=0"`
+	// Middlewares are applied in order, the first one outermost. List
+	// recover after tracing, logger and metrics so they see panics as 500s.
+	Middlewares []string `yaml:"middlewares" validate:"dive,oneof=requestid recover tracing logger gzip metrics"`
+}
+
+type ValidationConfig struct {
+	Sink          string         `yaml:"sink" validate:"required,oneof=csv jsonl stdout none"`
+	SinkPath      string         `yaml:"sinkPath" validate:"required"`
+	BufferSize    int            `yaml:"bufferSize" validate:"gte=1"`
+	BatchSize     int            `yaml:"batchSize" validate:"gte=1"`
+	FlushInterval time.Duration  `yaml:"flushInterval" validate:"gt=0"`
+	BlockWhenFull bool           `yaml:"blockWhenFull"`
+	Rotation      RotationConfig `yaml:"rotation"`
+	// QueryMaxRecords bounds the records in the time range of one query of
+	// the recorded failures, 0 reads them all.
+	QueryMaxRecords int `yaml:"queryMaxRecords" validate:"gte=0"`
+}
+
+// RotationConfig bounds the disk used by the CSV sink.
+type RotationConfig struct {
+	MaxSizeMB  int  `yaml:"maxSizeMB" validate:"gte=0"`
+	Daily      bool `yaml:"daily"`
+	MaxBackups int  `yaml:"maxBackups" validate:"gte=0"`
+	Compress   bool `yaml:"compress"`
+}
+
+type GameConfig struct {
+	CatName string `yaml:"catName" validate:"required,max=30"`
+}
+
+type StorageConfig struct {
+	Driver string `yaml:"driver" validate:"required,oneof=memory sqlite"`
+	Path   string `yaml:"path" validate:"required_if=Driver sqlite"`
+}
+
+type LogConfig struct {
+	Level string `yaml:"level" validate:"required,oneof=debug info warn error"`
+}
+
+type TracingConfig struct {
+	Exporter    string `yaml:"exporter" validate:"required,oneof=none stdout otlp"`
+	ServiceName string `yaml:"serviceName" validate:"required"`
+}
+
+// RateLimitConfig throttles the /api/v1 routes per client.
+type RateLimitConfig struct {
+	Enabled bool          `yaml:"enabled"`
+	Default RateLimitRule `yaml:"default"`
+	// Routes overrides Default for the listed route paths, such as
+	// /api/v1/guess-cat.
+	Routes map[string]RateLimitRule `yaml:"routes" validate:"dive,keys,startswith=/,endkeys"`
+}
+
+// RateLimitRule is a token bucket of Burst tokens gaining one each Every,
+// per client IP or userId.
+type RateLimitRule struct {
+	Burst int           `yaml:"burst" validate:"gte=1"`
+	Every time.Duration `yaml:"every" validate:"gt=0"`
+	Key   string        `yaml:"key" validate:"oneof=ip userId"`
+}
+
+// AdminConfig guards the /admin routes. They are not served unless Token is
+// set, requests then send it as a bearer token.
+type AdminConfig struct {
+	Token string `yaml:"token" validate:"omitempty,min=32"`
+}
+
+// Default returns the settings used when nothing else is configured.
+func Default() Config {
+	return Config{
+		Server: ServerConfig{
+			Addr:            ":1323",
+			ShutdownTimeout: 10 * time.Second,
+			Middlewares:     []string{"requestid", "tracing", "logger", "metrics", "recover"},
+		},
+		Validation: ValidationConfig{
+			Sink:          "csv",
+			SinkPath:      "validation_errors.csv",
+			BufferSize:    1024,
+			BatchSize:     128,
+			FlushInterval: time.Second,
+			Rotation: RotationConfig{
+				MaxSizeMB:  10,
+				MaxBackups: 5,
+				Compress:   true,
+			},
+			QueryMaxRecords: 1_000_000,
+		},
+		Game: GameConfig{
+			CatName: "Mittens",
+		},
+		Storage: StorageConfig{
+			Driver: "memory",
+			Path:   "data.db",
+		},
+		Log: LogConfig{
+			Level: "info",
+		},
+		Tracing: TracingConfig{
+			Exporter:    "none",
+			ServiceName: "medium-go-di",
+		},
+		RateLimit: RateLimitConfig{
+			Enabled: true,
+			Default: RateLimitRule{Burst: 100, Every: 10 * time.Millisecond, Key: "ip"},
+			Routes: map[string]RateLimitRule{
+				// guesses are cheap to brute force, the game also caps attempts per userId
+				"/api/v1/guess-cat": {Burst: 5, Every: 2 * time.Second, Key: "ip"},
+			},
+		},
+	}
+}
+
+// Load builds the config from defaults, the optional CONFIG_FILE (YAML or
+// JSON) and environment variables, then validates it.
+func Load() (Config, error) {
+	cfg := Default()
+
+	if path, ok := os.LookupEnv("CONFIG_FILE"); ok && path != "" {
+		if err := loadFile(path, &cfg); err != nil {
+			return Config{}, err
+		}
+	}
+
+	if err := loadEnv(&cfg); err != nil {
+		return Config{}, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+// Validate checks the config with the same validator used for requests.
+func (c Config) Validate() error {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	if err := v.Struct(c); err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+	return nil
+}
+
+func loadFile(path string, cfg *Config) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read config file: %w", err)
+	}
+	// JSON is valid YAML, so one decoder handles both formats
+	if err := yaml.Unmarshal(b, cfg); err != nil {
+		return fmt.Errorf("parse config file %s: %w", path, err)
+	}
+	return nil
+}
+
+func loadEnv(cfg *Config) error {
+	envString("SERVER_ADDR", &cfg.Server.Addr)
+	envList("SERVER_MIDDLEWARES", &cfg.Server.Middlewares)
+	envString("VALIDATION_SINK", &cfg.Validation.Sink)
+	envString("VALIDATION_SINK_PATH", &cfg.Validation.SinkPath)
+	envString("CAT_NAME", &cfg.Game.CatName)
+	envString("STORAGE_DRIVER", &cfg.Storage.Driver)
+	envString("STORAGE_PATH", &cfg.Storage.Path)
+	envString("LOG_LEVEL", &cfg.Log.Level)
+	envString("TRACING_EXPORTER", &cfg.Tracing.Exporter)
+	envString("OTEL_SERVICE_NAME", &cfg.Tracing.ServiceName)
+	envString("ADMIN_TOKEN", &cfg.Admin.Token)
+
+	if err := envDuration("SHUTDOWN_TIMEOUT", &cfg.Server.ShutdownTimeout); err != nil {
+		return err
+	}
+	if err := envInt("VALIDATION_SINK_BUFFER_SIZE", &cfg.Validation.BufferSize); err != nil {
+		return err
+	}
+	if err := envInt("VALIDATION_SINK_BATCH_SIZE", &cfg.Validation.BatchSize); err != nil {
+		return err
+	}
+	if err := envDuration("VALIDATION_SINK_FLUSH_INTERVAL", &cfg.Validation.FlushInterval); err != nil {
+		return err
+	}
+	if err := envBool("VALIDATION_SINK_BLOCK", &cfg.Validation.BlockWhenFull); err != nil {
+		return err
+	}
+	if err := envInt("VALIDATION_ROTATE_MAX_SIZE_MB", &cfg.Validation.Rotation.MaxSizeMB); err != nil {
+		return err
+	}
+	if err := envBool("VALIDATION_ROTATE_DAILY", &cfg.Validation.Rotation.Daily); err != nil {
+		return err
+	}
+	if err := envInt("VALIDATION_ROTATE_MAX_BACKUPS", &cfg.Validation.Rotation.MaxBackups); err != nil {
+		return err
+	}
+	if err := envBool("VALIDATION_ROTATE_COMPRESS", &cfg.Validation.Rotation.Compress); err != nil {
+		return err
+	}
+	if err := envInt("VALIDATION_QUERY_MAX_RECORDS", &cfg.Validation.QueryMaxRecords); err != nil {
+		return err
+	}
+	if err := envBool("RATE_LIMIT_ENABLED", &cfg.RateLimit.Enabled); err != nil {
+		return err
+	}
+	if err := envInt("RATE_LIMIT_BURST", &cfg.RateLimit.Default.Burst); err != nil {
+		return err
+	}
+	if err := envDuration("RATE_LIMIT_EVERY", &cfg.RateLimit.Default.Every); err != nil {
+		return err
+	}
+	return nil
+}
+
+func envString(key string, dst *string) {
+	if val, ok := os.LookupEnv(key); ok {
+		*dst = val
+	}
+}
+
+func envList(key string, dst *[]string) {
+	val, ok := os.LookupEnv(key)
+	if !ok {
+		return
+	}
+	list := []string{}
+	for _, item := range strings.Split(val, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	*dst = list
+}
+
+func envInt(key string, dst *int) error {
+	val, ok := os.LookupEnv(key)
+	if !ok {
+		return nil
+	}
+	n, err := strconv.Atoi(val)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", key, err)
+	}
+	*dst = n
+	return nil
+}
+
+func envBool(key string, dst *bool) error {
+	val, ok := os.LookupEnv(key)
+	if !ok {
+		return nil
+	}
+	b, err := strconv.ParseBool(val)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", key, err)
+	}
+	*dst = b
+	return nil
+}
+
+func envDuration(key string, dst *time.Duration) error {
+	val, ok := os.LookupEnv(key)
+	if !ok {
+		return nil
+	}
+	d, err := time.ParseDuration(val)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", key, err)
+	}
+	*dst = d
+	return nil
+}
@@ -0,0 +1,98 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_Defaults(t *testing.T) {
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, Default(), cfg)
+}
+
+func TestLoad_YAMLFile(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "example.yaml")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"requestid", "tracing", "logger", "metrics", "recover"}, cfg.Server.Middlewares)
+	assert.Equal(t, "jsonl", cfg.Validation.Sink)
+	assert.Equal(t, time.Second, cfg.Validation.FlushInterval)
+	assert.Equal(t, RotationConfig{MaxSizeMB: 10, Daily: true, MaxBackups: 7, Compress: true}, cfg.Validation.Rotation)
+	assert.Equal(t, RateLimitRule{Burst: 5, Every: 2 * time.Second, Key: "ip"}, cfg.RateLimit.Routes["/api/v1/guess-cat"])
+}
+
+func TestLoad_JSONFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"server": {"addr": "127.0.0.1:8080", "shutdownTimeout": "3s"}}`), 0644))
+	t.Setenv("CONFIG_FILE", path)
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, "127.0.0.1:8080", cfg.Server.Addr)
+	assert.Equal(t, 3*time.Second, cfg.Server.ShutdownTimeout)
+	// untouched sections keep their defaults
+	assert.Equal(t, Default().Validation, cfg.Validation)
+}
+
+func TestLoad_EnvOverridesFile(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "example.yaml")
+	t.Setenv("SERVER_ADDR", ":9000")
+	t.Setenv("SERVER_MIDDLEWARES", "recover, gzip")
+	t.Setenv("VALIDATION_SINK", "stdout")
+	t.Setenv("VALIDATION_SINK_BLOCK", "true")
+	t.Setenv("VALIDATION_SINK_BATCH_SIZE", "16")
+	t.Setenv("VALIDATION_ROTATE_MAX_SIZE_MB", "0")
+	t.Setenv("VALIDATION_ROTATE_COMPRESS", "false")
+	t.Setenv("VALIDATION_QUERY_MAX_RECORDS", "5000")
+	t.Setenv("RATE_LIMIT_ENABLED", "false")
+	t.Setenv("RATE_LIMIT_EVERY", "1s")
+	t.Setenv("ADMIN_TOKEN", "0123456789abcdef0123456789abcdef")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, ":9000", cfg.Server.Addr)
+	assert.Equal(t, []string{"recover", "gzip"}, cfg.Server.Middlewares)
+	assert.Equal(t, "stdout", cfg.Validation.Sink)
+	assert.True(t, cfg.Validation.BlockWhenFull)
+	assert.Equal(t, 16, cfg.Validation.BatchSize)
+	assert.Equal(t, RotationConfig{MaxSizeMB: 0, Daily: true, MaxBackups: 7, Compress: false}, cfg.Validation.Rotation)
+	assert.Equal(t, 5000, cfg.Validation.QueryMaxRecords)
+	assert.False(t, cfg.RateLimit.Enabled)
+	assert.Equal(t, RateLimitRule{Burst: 100, Every: time.Second, Key: "ip"}, cfg.RateLimit.Default)
+	assert.Equal(t, "0123456789abcdef0123456789abcdef", cfg.Admin.Token)
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		key  string
+		val  string
+	}{
+		{name: "bad addr", key: "SERVER_ADDR", val: "not an addr"},
+		{name: "unknown middleware", key: "SERVER_MIDDLEWARES", val: "recover,cors"},
+		{name: "unknown sink", key: "VALIDATION_SINK", val: "kafka"},
+		{name: "zero buffer", key: "VALIDATION_SINK_BUFFER_SIZE", val: "0"},
+		{name: "unparsable int", key: "VALIDATION_SINK_BATCH_SIZE", val: "many"},
+		{name: "negative backups", key: "VALIDATION_ROTATE_MAX_BACKUPS", val: "-1"},
+		{name: "negative query max records", key: "VALIDATION_QUERY_MAX_RECORDS", val: "-1"},
+		{name: "zero burst", key: "RATE_LIMIT_BURST", val: "0"},
+		{name: "short admin token", key: "ADMIN_TOKEN", val: "secret"},
+		{name: "unparsable duration", key: "SHUTDOWN_TIMEOUT", val: "soon"},
+		{name: "missing file", key: "CONFIG_FILE", val: "does-not-exist.yaml"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(tt.key, tt.val)
+			_, err := Load()
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testAdminToken = "0123456789abcdef0123456789abcdef"
+
+func TestAdminAuth(t *testing.T) {
+	tests := []struct {
+		name          string
+		authorization string
+		code          int
+	}{
+		{name: "valid token", authorization: "Bearer " + testAdminToken, code: http.StatusOK},
+		{name: "missing header", authorization: "", code: http.StatusUnauthorized},
+		{name: "wrong token", authorization: "Bearer not-the-token", code: http.StatusUnauthorized},
+		{name: "token prefix", authorization: "Bearer " + testAdminToken[:8], code: http.StatusUnauthorized},
+		{name: "basic scheme", authorization: "Basic " + testAdminToken, code: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			e := echo.New()
+			e.GET("/admin/validation-errors", func(c echo.Context) error {
+				return c.JSON(http.StatusOK, newOkResponse())
+			}, AdminAuth(testAdminToken))
+			httpReq := httptest.NewRequest(http.MethodGet, "/admin/validation-errors", nil)
+			if tt.authorization != "" {
+				httpReq.Header.Set(echo.HeaderAuthorization, tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+
+			// Test
+			e.ServeHTTP(rec, httpReq)
+
+			// Assert
+			assert.Equal(t, tt.code, rec.Code)
+			if tt.code != http.StatusUnauthorized {
+				return
+			}
+			assert.Equal(t, "Bearer", rec.Header().Get(echo.HeaderWWWAuthenticate))
+			var resp Response
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+			assert.Equal(t, Response{IsOK: false, Code: CodeUnauthorized, Msg: unauthorized}, resp)
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+
+	"github.com/BoomNooB/medium-go-di/redact"
+	"github.com/BoomNooB/medium-go-di/validatorwrapper"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// MIMEApplicationNDJSON is the content type of JSON Lines bodies.
+	MIMEApplicationNDJSON = "application/x-ndjson"
+
+	// maxBatchLineSize bounds the memory used by a single line of a batch.
+	maxBatchLineSize = 1 << 20
+
+	batchLineTooLong = "line too long"
+	batchReadFailed  = "failed to read body"
+)
+
+// requestKinds maps a request kind to its request type. Keep it in sync with
+// the oneof rule of BatchRequest.
+var requestKinds = map[string]func() any{
+	"favorite":  func() any { return new(FavoriteNumRequest) },
+	"pet-name":  func() any { return new(PetNameRequest) },
+	"thai-cid":  func() any { return new(ThaiCIDRequest) },
+	"guess-cat": func() any { return new(GuessCatNameRequest) },
+}
+
+// NewRequest returns a pointer to a new request of the given kind, such as
+// favorite or thai-cid.
+func NewRequest(kind string) (any, bool) {
+	newRequest, ok := requestKinds[kind]
+	if !ok {
+		return nil, false
+	}
+	return newRequest(), true
+}
+
+// RequestKinds returns the supported request kinds, sorted.
+func RequestKinds() []string {
+	kinds := make([]string, 0, len(requestKinds))
+	for kind := range requestKinds {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+type BatchRequest struct {
+	Kind string `param:"kind" validate:"required,oneof=favorite pet-name thai-cid guess-cat"`
+}
+
+// BatchLineResult is written for every non blank line of a batch.
+type BatchLineResult struct {
+	Line int `json:"line"`
+	Response
+}
+
+type BatchHandler struct {
+	v      Valiator
+	logger *slog.Logger
+}
+
+// NewBatchHandler validates JSON Lines bodies line by line. It only runs the
+// validation, the business logic of each endpoint is not applied.
+func NewBatchHandler(validator Valiator, logger *slog.Logger) *BatchHandler {
+	return &BatchHandler{
+		v:      validator,
+		logger: logger,
+	}
+}
+
+// ValidateBatch streams back one BatchLineResult per line as soon as the
+// line is validated, so the body is never held in memory as a whole.
+func (bh *BatchHandler) ValidateBatch(c echo.Context) error {
+	ctx := validationContext(c)
+
+	var req BatchRequest
+	if err := (&echo.DefaultBinder{}).BindPathParams(c, &req); err != nil {
+		return respond(c, http.StatusBadRequest, newBadRequestResponse(CodeBadRequest, badRequestNotValid))
+	}
+	if err := bh.v.StructValidation(ctx, &req); err != nil {
+		if errors.Is(err, validatorwrapper.ErrValidationFailed) {
+			return respond(c, http.StatusBadRequest, newValidationFailedResponse(err))
+		}
+		return internalError(c, bh.logger, &req, err)
+	}
+
+	// HTTP/1 servers drop the unread body once the response starts, unless
+	// reading and writing may overlap. The status is only written with the
+	// first result, after the body was first read, so that clients waiting
+	// for 100 Continue get it. Test recorders cannot enable full duplex.
+	res := c.Response()
+	_ = http.NewResponseController(res).EnableFullDuplex()
+	res.Header().Set(echo.HeaderContentType, MIMEApplicationNDJSON)
+	enc := json.NewEncoder(res)
+
+	scanner := bufio.NewScanner(c.Request().Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxBatchLineSize)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if ctx.Err() != nil {
+			return nil
+		}
+		b := bytes.TrimSpace(scanner.Bytes())
+		if len(b) == 0 {
+			continue
+		}
+
+		lineReq, _ := NewRequest(req.Kind)
+		result := BatchLineResult{Line: line, Response: bh.validateLine(ctx, c, lineReq, b)}
+		if err := enc.Encode(result); err != nil {
+			// the client went away
+			return nil
+		}
+		res.Flush()
+	}
+
+	if err := scanner.Err(); err != nil {
+		resp := newBadRequestResponse(CodeBadRequest, batchReadFailed)
+		if errors.Is(err, bufio.ErrTooLong) {
+			resp = newBadRequestResponse(CodeLineTooLong, batchLineTooLong)
+		}
+		bh.logger.WarnContext(ctx, "batch stopped", "kind", req.Kind, "line", line+1, "error", err)
+		enc.Encode(BatchLineResult{Line: line + 1, Response: resp})
+		res.Flush()
+	}
+	return nil
+}
+
+func (bh *BatchHandler) validateLine(ctx context.Context, c echo.Context, req any, b []byte) Response {
+	if err := json.Unmarshal(b, req); err != nil {
+		return newBadRequestResponse(CodeJSONSyntax, badRequestJSONSyntax)
+	}
+
+	err := bh.v.StructValidation(ctx, req)
+	if err == nil {
+		return newOkResponse()
+	}
+	if errors.Is(err, validatorwrapper.ErrValidationFailed) {
+		return newValidationFailedResponse(err)
+	}
+	bh.logger.ErrorContext(ctx, "internal error",
+		"route", c.Path(),
+		"request_type", fmt.Sprintf("%T", req),
+		"request", redact.Struct(req),
+		"error", err,
+	)
+	return newInternalErrorResponse()
+}
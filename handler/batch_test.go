@@ -0,0 +1,203 @@
+package handler
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/BoomNooB/medium-go-di/validatorwrapper"
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func validateBatch(t *testing.T, kind, body string, headers ...string) (*httptest.ResponseRecorder, []BatchLineResult) {
+	t.Helper()
+	e := echo.New()
+	httpReq := httptest.NewRequest(http.MethodPost, "/api/v1/batch/"+kind, strings.NewReader(body))
+	httpReq.Header.Set(echo.HeaderContentType, MIMEApplicationNDJSON)
+	for i := 0; i+1 < len(headers); i += 2 {
+		httpReq.Header.Set(headers[i], headers[i+1])
+	}
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httpReq, rec)
+	c.SetParamNames("kind")
+	c.SetParamValues(kind)
+
+	v := validator.New(validator.WithRequiredStructEnabled())
+	vw := validatorwrapper.NewValidatorWrapper(v, validatorwrapper.NewNopSink(), testLogger)
+	require.NoError(t, NewBatchHandler(vw, testLogger).ValidateBatch(c))
+
+	var results []BatchLineResult
+	if rec.Header().Get(echo.HeaderContentType) != MIMEApplicationNDJSON {
+		return rec, nil
+	}
+	scanner := bufio.NewScanner(rec.Body)
+	for scanner.Scan() {
+		var r BatchLineResult
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &r))
+		results = append(results, r)
+	}
+	return rec, results
+}
+
+func TestBatchHandler_ValidateBatch(t *testing.T) {
+	// Setup
+	body := strings.Join([]string{
+		`{"userId":"550e8400-e29b-41d4-a716-446655440000","favNum":7}`,
+		`{"userId":"not-a-uuid","favNum":7}`,
+		``,
+		`{"userId":`,
+	}, "\n")
+
+	// Test
+	rec, results := validateBatch(t, "favorite", body)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, rec.Code)
+	require.Len(t, results, 3)
+
+	assert.Equal(t, 1, results[0].Line)
+	assert.True(t, results[0].IsOK)
+
+	assert.Equal(t, 2, results[1].Line)
+	assert.False(t, results[1].IsOK)
+	assert.Equal(t, badRequestNotValid, results[1].Msg)
+	require.Len(t, results[1].Errors, 1)
+	assert.Equal(t, "userId", results[1].Errors[0].Field)
+
+	// blank lines are skipped but still counted
+	assert.Equal(t, 4, results[2].Line)
+	assert.Equal(t, badRequestJSONSyntax, results[2].Msg)
+}
+
+func TestBatchHandler_ValidateBatch_Kinds(t *testing.T) {
+	lines := map[string]string{
+		"favorite":  `{"userId":"550e8400-e29b-41d4-a716-446655440000","favNum":7}`,
+		"pet-name":  `{"ownerId":"550e8400-e29b-41d4-a716-446655440000","petName":"Buddy"}`,
+		"thai-cid":  `{"citizenId":"1234567890121","fullName":"Somchai Jaidee"}`,
+		"guess-cat": `{"userId":"550e8400-e29b-41d4-a716-446655440000","guessName":"Fluffy"}`,
+	}
+	require.Len(t, lines, len(RequestKinds()))
+
+	for kind, line := range lines {
+		_, results := validateBatch(t, kind, line)
+		require.Len(t, results, 1, kind)
+		assert.True(t, results[0].IsOK, kind)
+	}
+}
+
+func TestBatchHandler_ValidateBatch_UnknownKind(t *testing.T) {
+	// Test
+	rec, _ := validateBatch(t, "unknown", `{}`)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	var resp Response
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, badRequestNotValid, resp.Msg)
+	require.Len(t, resp.Errors, 1)
+	assert.Equal(t, "oneof", resp.Errors[0].Tag)
+}
+
+func TestBatchHandler_ValidateBatch_LineTooLong(t *testing.T) {
+	// Setup
+	body := `{"userId":"550e8400-e29b-41d4-a716-446655440000","favNum":7}` + "\n" +
+		`{"userId":"` + strings.Repeat("a", maxBatchLineSize) + `"}`
+
+	// Test
+	rec, results := validateBatch(t, "favorite", body)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, rec.Code)
+	require.Len(t, results, 2)
+	assert.True(t, results[0].IsOK)
+	assert.Equal(t, 2, results[1].Line)
+	assert.Equal(t, batchLineTooLong, results[1].Msg)
+}
+
+func TestBatchHandler_ValidateBatch_TranslatesEveryTag(t *testing.T) {
+	// every validate tag of the request structs fails at least once
+	lines := map[string]string{
+		"favorite":  `{"userId":"not-a-uuid","favNum":-1}` + "\n" + `{}`,
+		"pet-name":  `{"ownerId":"x","petName":"B"}` + "\n" + `{"petName":"` + strings.Repeat("B", 51) + `"}`,
+		"thai-cid":  `{"citizenId":"12345","fullName":"AB"}` + "\n" + `{"citizenId":"123456789012A"}` + "\n" + `{"citizenId":"1234567890123"}`,
+		"guess-cat": `{"guessName":"` + strings.Repeat("a", 31) + `"}`,
+	}
+
+	for _, locale := range []string{"en", "th"} {
+		for kind, body := range lines {
+			rec, results := validateBatch(t, kind, body, headerAcceptLanguage, locale)
+			assert.Equal(t, locale, rec.Header().Get(headerContentLanguage))
+			for _, r := range results {
+				require.NotEmpty(t, r.Errors, "%s line %d", kind, r.Line)
+				for _, f := range r.Errors {
+					// untranslated tags fall back to the raw validator error
+					assert.NotContains(t, f.Message, "Error:Field validation", "%s %s %s", locale, kind, f.Tag)
+					assert.NotEmpty(t, f.Message)
+				}
+			}
+		}
+	}
+}
+
+func TestBatchHandler_ValidateBatch_LargeBodyOverHTTP(t *testing.T) {
+	e := echo.New()
+	v := validator.New(validator.WithRequiredStructEnabled())
+	vw := validatorwrapper.NewValidatorWrapper(v, validatorwrapper.NewNopSink(), testLogger)
+	e.POST("/api/v1/batch/:kind", NewBatchHandler(vw, testLogger).ValidateBatch)
+	srv := httptest.NewServer(e)
+	defer srv.Close()
+
+	// big enough to be read after the first results are flushed
+	const lines = 5000
+	var body strings.Builder
+	for i := 0; i < lines; i++ {
+		body.WriteString(`{"userId":"550e8400-e29b-41d4-a716-446655440000","favNum":7}` + "\n")
+	}
+
+	tests := []struct {
+		name    string
+		size    int
+		headers map[string]string
+	}{
+		{name: "under the server discard limit", size: 190 << 10},
+		{name: "over the server discard limit", size: body.Len()},
+		{name: "expect continue", size: body.Len(), headers: map[string]string{"Expect": "100-continue"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			payload := body.String()[:tt.size]
+			payload = payload[:strings.LastIndex(payload, "\n")+1]
+			httpReq, err := http.NewRequest(http.MethodPost, srv.URL+"/api/v1/batch/favorite", strings.NewReader(payload))
+			require.NoError(t, err)
+			httpReq.Header.Set(echo.HeaderContentType, MIMEApplicationNDJSON)
+			for k, v := range tt.headers {
+				httpReq.Header.Set(k, v)
+			}
+
+			// Test
+			httpResp, err := srv.Client().Do(httpReq)
+			require.NoError(t, err)
+			defer httpResp.Body.Close()
+
+			// Assert
+			assert.Equal(t, http.StatusOK, httpResp.StatusCode)
+			count := 0
+			scanner := bufio.NewScanner(httpResp.Body)
+			for scanner.Scan() {
+				var r BatchLineResult
+				require.NoError(t, json.Unmarshal(scanner.Bytes(), &r))
+				require.True(t, r.IsOK, "line %d: %s", r.Line, r.Msg)
+				count++
+			}
+			require.NoError(t, scanner.Err())
+			assert.Equal(t, strings.Count(payload, "\n"), count)
+		})
+	}
+}
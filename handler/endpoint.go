@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/BoomNooB/medium-go-di/redact"
+	"github.com/BoomNooB/medium-go-di/validatorwrapper"
+	"github.com/labstack/echo/v4"
+)
+
+// BusinessFunc runs once a request has passed validation and returns the
+// response to send. Returning an error answers with an internal error,
+// unless it was built with newStatusError.
+type BusinessFunc[T any] func(ctx context.Context, req *T) (Response, error)
+
+// statusError lets a BusinessFunc answer with a status other than 200.
+type statusError struct {
+	code int
+	resp Response
+}
+
+func newStatusError(code int, resp Response) error {
+	return &statusError{code: code, resp: resp}
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("status %d: %s", e.code, e.resp.Msg)
+}
+
+// ValidateEndpoint builds the bind -> validate -> respond flow shared by all
+// endpoints for the request type T. fn may be nil when the endpoint only
+// validates.
+func ValidateEndpoint[T any](v Valiator, logger *slog.Logger, fn BusinessFunc[T]) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := validationContext(c)
+		req := new(T)
+		err := bindParams(c, req)
+		if err != nil {
+			return respond(c,
+				http.StatusBadRequest,
+				newBadRequestResponse(CodeBadRequest, badRequestParams),
+			)
+		}
+		err = (&echo.DefaultBinder{}).BindBody(c, req)
+		if err != nil {
+			return respond(c,
+				http.StatusBadRequest,
+				newBadRequestResponse(CodeJSONSyntax, badRequestJSONSyntax),
+			)
+		}
+
+		err = v.StructValidation(ctx, req)
+		if err != nil {
+			// check if it's a validation error or not
+			if errors.Is(err, validatorwrapper.ErrValidationFailed) {
+				return respond(c,
+					http.StatusBadRequest,
+					newValidationFailedResponse(err),
+				)
+			}
+
+			// else it's an internal error
+			return internalError(c, logger, req, err)
+		}
+
+		resp := newOkResponse()
+		if fn != nil {
+			resp, err = fn(ctx, req)
+			if err != nil {
+				var se *statusError
+				if errors.As(err, &se) {
+					return respond(c, se.code, se.resp)
+				}
+				return internalError(c, logger, req, err)
+			}
+		}
+
+		logger.DebugContext(ctx, "request is valid", "request", fmt.Sprintf("%T", *req))
+		return respond(c,
+			http.StatusOK,
+			resp,
+		)
+	}
+}
+
+// bindParams binds the path parameters, and the query string of the methods
+// without a body, the way echo.DefaultBinder does before the body.
+func bindParams(c echo.Context, req any) error {
+	b := &echo.DefaultBinder{}
+	err := b.BindPathParams(c, req)
+	if err != nil {
+		return err
+	}
+	method := c.Request().Method
+	if method == http.MethodGet || method == http.MethodDelete || method == http.MethodHead {
+		return b.BindQueryParams(c, req)
+	}
+	return nil
+}
+
+const (
+	headerAcceptLanguage  = "Accept-Language"
+	headerContentLanguage = "Content-Language"
+)
+
+// validationContext returns the request context given to the validator. It
+// carries the locale picked from Accept-Language for the validation
+// messages, telling the client which one was used, and the request metadata
+// recorded with each validation failure. The validator picks the request ID
+// from the context itself.
+func validationContext(c echo.Context) context.Context {
+	req := c.Request()
+	locale := validatorwrapper.MatchLocale(req.Header.Get(headerAcceptLanguage))
+	c.Response().Header().Set(headerContentLanguage, locale)
+	ctx := validatorwrapper.ContextWithLocale(req.Context(), locale)
+
+	return validatorwrapper.ContextWithRequestMeta(ctx, validatorwrapper.RequestMeta{
+		Route:     c.Path(),
+		Method:    req.Method,
+		RemoteIP:  c.RealIP(),
+		UserAgent: req.UserAgent(),
+	})
+}
+
+// internalError logs err with the request content, redacting the fields
+// tagged with pii, and answers with a generic message.
+func internalError[T any](c echo.Context, logger *slog.Logger, req *T, err error) error {
+	logger.ErrorContext(c.Request().Context(), "internal error",
+		"route", c.Path(),
+		"request_type", fmt.Sprintf("%T", *req),
+		"request", redact.Struct(req),
+		"error", err,
+	)
+	return respond(c,
+		http.StatusInternalServerError,
+		newInternalErrorResponse(),
+	)
+}
@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/BoomNooB/medium-go-di/requestid"
+	"github.com/BoomNooB/medium-go-di/validatorwrapper"
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type echoRequest struct {
+	Word string `json:"word" validate:"required,alpha"`
+}
+
+func serveEndpoint(h echo.HandlerFunc, body string) *httptest.ResponseRecorder {
+	e := echo.New()
+	httpReq := httptest.NewRequest(http.MethodPost, "/echo", bytes.NewReader([]byte(body)))
+	httpReq.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httpReq, rec)
+	h(c)
+	return rec
+}
+
+func TestValidateEndpoint_BusinessFunc(t *testing.T) {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	vw := validatorwrapper.NewValidatorWrapper(v, validatorwrapper.NewNopSink(), testLogger)
+
+	var got *echoRequest
+	h := ValidateEndpoint(vw, testLogger, func(ctx context.Context, req *echoRequest) (Response, error) {
+		got = req
+		return Response{IsOK: true, Msg: "hello " + req.Word}, nil
+	})
+
+	rec := serveEndpoint(h, `{"word": "cat"}`)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var resp Response
+	json.Unmarshal(rec.Body.Bytes(), &resp)
+	assert.True(t, resp.IsOK)
+	assert.Equal(t, "hello cat", resp.Msg)
+	assert.Equal(t, &echoRequest{Word: "cat"}, got)
+}
+
+func TestValidateEndpoint_BusinessFuncNotCalledWhenInvalid(t *testing.T) {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	vw := validatorwrapper.NewValidatorWrapper(v, validatorwrapper.NewNopSink(), testLogger)
+
+	called := false
+	h := ValidateEndpoint(vw, testLogger, func(ctx context.Context, req *echoRequest) (Response, error) {
+		called = true
+		return newOkResponse(), nil
+	})
+
+	rec := serveEndpoint(h, `{"word": "c4t"}`)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.False(t, called)
+}
+
+func TestValidateEndpoint_BusinessFuncError(t *testing.T) {
+	h := ValidateEndpoint(&mockValidator{}, testLogger, func(ctx context.Context, req *echoRequest) (Response, error) {
+		return Response{}, errors.New("boom")
+	})
+
+	rec := serveEndpoint(h, `{"word": "cat"}`)
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	var resp Response
+	json.Unmarshal(rec.Body.Bytes(), &resp)
+	assert.False(t, resp.IsOK)
+	assert.Equal(t, "internal server error", resp.Msg)
+}
+
+func TestValidateEndpoint_RecordsRequestMeta(t *testing.T) {
+	sink := &bufferSink{}
+	v := validator.New(validator.WithRequiredStructEnabled())
+	vw := validatorwrapper.NewValidatorWrapper(v, sink, testLogger)
+	h := requestid.Middleware()(ValidateEndpoint[echoRequest](vw, testLogger, nil))
+
+	e := echo.New()
+	httpReq := httptest.NewRequest(http.MethodPost, "/echo", bytes.NewReader([]byte(`{"word": "c4t"}`)))
+	httpReq.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	httpReq.Header.Set(echo.HeaderXRequestID, "req-1")
+	httpReq.Header.Set("User-Agent", "curl/8.5.0")
+	httpReq.RemoteAddr = "192.0.2.1:51234"
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httpReq, rec)
+	c.SetPath("/echo")
+	h(c)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	var resp Response
+	json.Unmarshal(rec.Body.Bytes(), &resp)
+	assert.Equal(t, "req-1", resp.RequestID)
+	assert.Len(t, sink.records, 1)
+	assert.Equal(t, validatorwrapper.RequestMeta{
+		Route:     "/echo",
+		Method:    http.MethodPost,
+		RequestID: "req-1",
+		RemoteIP:  "192.0.2.1",
+		UserAgent: "curl/8.5.0",
+	}, sink.records[0].RequestMeta)
+}
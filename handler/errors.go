@@ -0,0 +1,174 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/BoomNooB/medium-go-di/requestid"
+	"github.com/labstack/echo/v4"
+)
+
+// ErrorCode is the machine readable reason of a failed request. Codes are
+// part of the API: never rename one, only add new ones.
+type ErrorCode string
+
+const (
+	// Response codes
+	CodeBadRequest       ErrorCode = "BAD_REQUEST"
+	CodeJSONSyntax       ErrorCode = "JSON_SYNTAX"
+	CodeValidationFailed ErrorCode = "VALIDATION_FAILED"
+	CodeNotFound         ErrorCode = "NOT_FOUND"
+	CodeMethodNotAllowed ErrorCode = "METHOD_NOT_ALLOWED"
+	CodeLineTooLong      ErrorCode = "LINE_TOO_LONG"
+	CodeNotReady         ErrorCode = "NOT_READY"
+	CodeInternal         ErrorCode = "INTERNAL"
+	CodeRateLimited      ErrorCode = "RATE_LIMITED"
+	CodeUnauthorized     ErrorCode = "UNAUTHORIZED"
+	CodeTooManyRecords   ErrorCode = "TOO_MANY_RECORDS"
+
+	// Field error codes
+	CodeFieldRequired ErrorCode = "FIELD_REQUIRED"
+	CodeFieldInvalid  ErrorCode = "FIELD_INVALID"
+)
+
+// MIMEApplicationProblemJSON is the RFC 7807 content type, sent instead of
+// the Response envelope when the client accepts it.
+const MIMEApplicationProblemJSON = "application/problem+json"
+
+// Problem is the RFC 7807 form of an error Response. Code, Errors and Data
+// are extension members carrying the same content as the Response.
+type Problem struct {
+	Type     string       `json:"type"`
+	Title    string       `json:"title"`
+	Status   int          `json:"status"`
+	Detail   string       `json:"detail,omitempty"`
+	Instance string       `json:"instance,omitempty"`
+	Code     ErrorCode    `json:"code"`
+	Errors   []FieldError `json:"errors,omitempty"`
+	Data     any          `json:"data,omitempty"`
+	// RequestID is the X-Request-ID of the failed request.
+	RequestID string `json:"requestId,omitempty"`
+}
+
+// fieldErrorCode classifies a failed validate tag.
+func fieldErrorCode(tag string) ErrorCode {
+	if strings.HasPrefix(tag, "required") {
+		return CodeFieldRequired
+	}
+	return CodeFieldInvalid
+}
+
+// statusErrorCode is the code of errors that only carry an HTTP status, such
+// as the ones returned by Echo itself.
+func statusErrorCode(status int) ErrorCode {
+	switch status {
+	case http.StatusNotFound:
+		return CodeNotFound
+	case http.StatusMethodNotAllowed:
+		return CodeMethodNotAllowed
+	case http.StatusTooManyRequests:
+		return CodeRateLimited
+	}
+	if status >= http.StatusInternalServerError {
+		return CodeInternal
+	}
+	return CodeBadRequest
+}
+
+// wantsProblem reports whether the client asked for RFC 7807 errors.
+func wantsProblem(c echo.Context) bool {
+	return strings.Contains(c.Request().Header.Get(echo.HeaderAccept), MIMEApplicationProblemJSON)
+}
+
+// respond writes resp with status, as a Problem when it is an error and the
+// client accepts application/problem+json. Errors carry the request ID.
+func respond(c echo.Context, status int, resp Response) error {
+	if status < http.StatusBadRequest {
+		return c.JSON(status, resp)
+	}
+	resp.RequestID = requestid.FromContext(c.Request().Context())
+	if !wantsProblem(c) {
+		return c.JSON(status, resp)
+	}
+
+	problem := Problem{
+		Type:      "about:blank",
+		Title:     http.StatusText(status),
+		Status:    status,
+		Detail:    resp.Msg,
+		Instance:  c.Request().URL.Path,
+		Code:      resp.Code,
+		Errors:    resp.Errors,
+		Data:      resp.Data,
+		RequestID: resp.RequestID,
+	}
+	b, err := json.Marshal(problem)
+	if err != nil {
+		return err
+	}
+	return c.Blob(status, MIMEApplicationProblemJSON, b)
+}
+
+// WriteErrors writes the response of a failed handler with Echo's
+// HTTPErrorHandler, then still returns the error. Registered after the other
+// middlewares, it lets them read the final status and the error; the error
+// handler skips the response Echo would write again.
+func WriteErrors() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			err := next(c)
+			if err != nil {
+				c.Error(err)
+			}
+			return err
+		}
+	}
+}
+
+// NewHTTPErrorHandler maps every error that reaches Echo, including its own
+// 404 and 405, into the Response envelope.
+func NewHTTPErrorHandler(logger *slog.Logger) echo.HTTPErrorHandler {
+	return func(err error, c echo.Context) {
+		if c.Response().Committed {
+			return
+		}
+
+		status := http.StatusInternalServerError
+		var he *echo.HTTPError
+		if errors.As(err, &he) {
+			status = he.Code
+		} else {
+			logger.ErrorContext(c.Request().Context(), "unhandled error",
+				"route", c.Path(),
+				"error", err,
+			)
+		}
+
+		var resp Response
+		switch {
+		case status == http.StatusNotFound:
+			resp = newNotFoundResponse()
+		case status >= http.StatusInternalServerError:
+			// never leak internal error details
+			resp = newInternalErrorResponse()
+		default:
+			resp = Response{
+				IsOK: false,
+				Code: statusErrorCode(status),
+				Msg:  strings.ToLower(http.StatusText(status)),
+			}
+		}
+
+		if c.Request().Method == http.MethodHead {
+			err = c.NoContent(status)
+		} else {
+			err = respond(c, status, resp)
+		}
+		if err != nil {
+			logger.ErrorContext(c.Request().Context(), "failed to write error response", "error", err)
+		}
+	}
+}
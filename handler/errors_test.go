@@ -0,0 +1,169 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/BoomNooB/medium-go-di/requestid"
+	"github.com/BoomNooB/medium-go-di/validatorwrapper"
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newErrorTestServer registers a single route behind the error handler.
+func newErrorTestServer() *echo.Echo {
+	e := echo.New()
+	e.HTTPErrorHandler = NewHTTPErrorHandler(testLogger)
+
+	v := validator.New(validator.WithRequiredStructEnabled())
+	vw := validatorwrapper.NewValidatorWrapper(v, validatorwrapper.NewNopSink(), testLogger)
+	e.POST("/echo", ValidateEndpoint[echoRequest](vw, testLogger, nil))
+	e.GET("/boom", func(c echo.Context) error {
+		return errors.New("database is on fire")
+	})
+	return e
+}
+
+func serveError(e *echo.Echo, method, path, accept string, body string) *httptest.ResponseRecorder {
+	httpReq := httptest.NewRequest(method, path, bytes.NewReader([]byte(body)))
+	httpReq.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	if accept != "" {
+		httpReq.Header.Set(echo.HeaderAccept, accept)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httpReq)
+	return rec
+}
+
+func TestHTTPErrorHandler_Envelope(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		wantStatus int
+		wantCode   ErrorCode
+		wantMsg    string
+	}{
+		{
+			name:       "unknown route",
+			method:     http.MethodPost,
+			path:       "/api/v1/non-existent",
+			wantStatus: http.StatusNotFound,
+			wantCode:   CodeNotFound,
+			wantMsg:    notFound,
+		},
+		{
+			name:       "wrong method",
+			method:     http.MethodGet,
+			path:       "/echo",
+			wantStatus: http.StatusMethodNotAllowed,
+			wantCode:   CodeMethodNotAllowed,
+			wantMsg:    "method not allowed",
+		},
+		{
+			name:       "plain error",
+			method:     http.MethodGet,
+			path:       "/boom",
+			wantStatus: http.StatusInternalServerError,
+			wantCode:   CodeInternal,
+			wantMsg:    "internal server error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Test
+			rec := serveError(newErrorTestServer(), tt.method, tt.path, "", "")
+
+			// Assert
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Contains(t, rec.Header().Get(echo.HeaderContentType), echo.MIMEApplicationJSON)
+			var resp Response
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+			assert.Equal(t, Response{IsOK: false, Code: tt.wantCode, Msg: tt.wantMsg}, resp)
+		})
+	}
+}
+
+func TestHTTPErrorHandler_Head(t *testing.T) {
+	rec := serveError(newErrorTestServer(), http.MethodHead, "/missing", "", "")
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Empty(t, rec.Body.String())
+}
+
+func TestRespond_Problem(t *testing.T) {
+	// Setup
+	e := newErrorTestServer()
+
+	// Test
+	rec := serveError(e, http.MethodPost, "/echo", "application/problem+json, application/json;q=0.5", `{"word": "123"}`)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, MIMEApplicationProblemJSON, rec.Header().Get(echo.HeaderContentType))
+	var problem Problem
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+	assert.Equal(t, "about:blank", problem.Type)
+	assert.Equal(t, "Bad Request", problem.Title)
+	assert.Equal(t, http.StatusBadRequest, problem.Status)
+	assert.Equal(t, badRequestNotValid, problem.Detail)
+	assert.Equal(t, "/echo", problem.Instance)
+	assert.Equal(t, CodeValidationFailed, problem.Code)
+	require.Len(t, problem.Errors, 1)
+	assert.Equal(t, CodeFieldInvalid, problem.Errors[0].Code)
+	assert.Equal(t, "alpha", problem.Errors[0].Tag)
+}
+
+func TestRespond_ProblemOnlyForErrors(t *testing.T) {
+	// Setup
+	e := newErrorTestServer()
+
+	// Test
+	ok := serveError(e, http.MethodPost, "/echo", MIMEApplicationProblemJSON, `{"word": "hello"}`)
+	notFound := serveError(e, http.MethodGet, "/missing", MIMEApplicationProblemJSON, "")
+
+	// Assert
+	assert.Equal(t, http.StatusOK, ok.Code)
+	assert.Contains(t, ok.Header().Get(echo.HeaderContentType), echo.MIMEApplicationJSON)
+	assert.Equal(t, http.StatusNotFound, notFound.Code)
+	assert.Equal(t, MIMEApplicationProblemJSON, notFound.Header().Get(echo.HeaderContentType))
+}
+
+func TestRespond_RequestID(t *testing.T) {
+	// Setup
+	e := newErrorTestServer()
+	e.Use(requestid.Middleware())
+	serve := func(accept string) *httptest.ResponseRecorder {
+		httpReq := httptest.NewRequest(http.MethodGet, "/missing", nil)
+		httpReq.Header.Set(echo.HeaderXRequestID, "req-123")
+		httpReq.Header.Set(echo.HeaderAccept, accept)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httpReq)
+		return rec
+	}
+
+	// Test
+	envelope := serve(echo.MIMEApplicationJSON)
+	problem := serve(MIMEApplicationProblemJSON)
+
+	// Assert
+	var resp Response
+	require.NoError(t, json.Unmarshal(envelope.Body.Bytes(), &resp))
+	assert.Equal(t, "req-123", resp.RequestID)
+	assert.Equal(t, "req-123", envelope.Header().Get(echo.HeaderXRequestID))
+	var p Problem
+	require.NoError(t, json.Unmarshal(problem.Body.Bytes(), &p))
+	assert.Equal(t, "req-123", p.RequestID)
+}
+
+func TestFieldErrorCode(t *testing.T) {
+	assert.Equal(t, CodeFieldRequired, fieldErrorCode("required"))
+	assert.Equal(t, CodeFieldRequired, fieldErrorCode("required_if"))
+	assert.Equal(t, CodeFieldInvalid, fieldErrorCode("uuid_rfc4122"))
+}
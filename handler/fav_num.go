@@ -1,11 +1,12 @@
 package handler
 
 import (
+	"context"
 	"errors"
-	"log"
+	"log/slog"
 	"net/http"
 
-	"github.com/BoomNooB/medium-go-di/validatorwrapper"
+	"github.com/BoomNooB/medium-go-di/store"
 	"github.com/labstack/echo/v4"
 )
 
@@ -14,50 +15,59 @@ type FavoriteNumRequest struct {
 	FavNum int    `json:"favNum" validate:"required,gt=0"`
 }
 
+type GetFavoriteNumRequest struct {
+	UserID string `param:"userId" validate:"required,uuid_rfc4122"`
+}
+
+type FavoriteNumResult struct {
+	UserID string `json:"userId"`
+	FavNum int    `json:"favNum"`
+}
+
+// FavoriteRepo stores the favorite number of each user.
+type FavoriteRepo interface {
+	SaveFavorite(ctx context.Context, userID string, favNum int) error
+	// GetFavorite returns store.ErrNotFound when the user has none.
+	GetFavorite(ctx context.Context, userID string) (int, error)
+}
+
 type FavoriteNumHandler struct {
-	v Valiator
+	repo   FavoriteRepo
+	handle echo.HandlerFunc
+	get    echo.HandlerFunc
 }
 
-func NewFavoriteNumHandler(validator Valiator) *FavoriteNumHandler {
-	return &FavoriteNumHandler{
-		v: validator,
+func NewFavoriteNumHandler(validator Valiator, repo FavoriteRepo, logger *slog.Logger) *FavoriteNumHandler {
+	fh := &FavoriteNumHandler{
+		repo: repo,
 	}
+	fh.handle = ValidateEndpoint(validator, logger, fh.save)
+	fh.get = ValidateEndpoint(validator, logger, fh.load)
+	return fh
 }
 
 func (fh *FavoriteNumHandler) Favorite(c echo.Context) error {
-	ctx := c.Request().Context()
-	req := FavoriteNumRequest{}
-	err := c.Bind(&req)
-	if err != nil {
-		return c.JSON(
-			http.StatusBadRequest,
-			newBadRequestResponse(badRequestJSONSyntax),
-		)
+	return fh.handle(c)
+}
+
+func (fh *FavoriteNumHandler) GetFavorite(c echo.Context) error {
+	return fh.get(c)
+}
+
+func (fh *FavoriteNumHandler) save(ctx context.Context, req *FavoriteNumRequest) (Response, error) {
+	if err := fh.repo.SaveFavorite(ctx, req.UserID, req.FavNum); err != nil {
+		return Response{}, err
 	}
+	return newOkResponse(), nil
+}
 
-	err = fh.v.StructValidation(ctx, &req)
+func (fh *FavoriteNumHandler) load(ctx context.Context, req *GetFavoriteNumRequest) (Response, error) {
+	favNum, err := fh.repo.GetFavorite(ctx, req.UserID)
+	if errors.Is(err, store.ErrNotFound) {
+		return Response{}, newStatusError(http.StatusNotFound, newNotFoundResponse())
+	}
 	if err != nil {
-		// check if it's a validation error or not
-		if errors.Is(err, validatorwrapper.ErrValidationFailed) {
-			return c.JSON(
-				http.StatusBadRequest,
-				newBadRequestResponse(badRequestNotValid),
-			)
-		}
-
-		// else it's an internal error
-		// print both user, fav and error
-		log.Printf("User ID: %s, Favorite Number: %d\n", req.UserID, req.FavNum)
-		log.Printf("Error: %v\n", err)
-		return c.JSON(
-			http.StatusInternalServerError,
-			newInternalErrorResponse(),
-		)
+		return Response{}, err
 	}
-
-	log.Println("Yay!")
-	return c.JSON(
-		http.StatusOK,
-		newOkResponse(),
-	)
+	return newDataResponse(FavoriteNumResult{UserID: req.UserID, FavNum: favNum}), nil
 }
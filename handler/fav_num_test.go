@@ -9,6 +9,7 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/BoomNooB/medium-go-di/store"
 	"github.com/BoomNooB/medium-go-di/validatorwrapper"
 	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
@@ -24,6 +25,23 @@ func (m *mockValidator) StructValidation(ctx context.Context, req any) error {
 	return m.err
 }
 
+// bufferSink keeps the validation error records in memory
+type bufferSink struct {
+	records []validatorwrapper.ErrorRecord
+}
+
+func (b *bufferSink) Write(ctx context.Context, records []validatorwrapper.ErrorRecord) error {
+	b.records = append(b.records, records...)
+	return nil
+}
+
+// failingSink simulates a validation error sink that cannot write, e.g. a full disk
+type failingSink struct{}
+
+func (failingSink) Write(ctx context.Context, records []validatorwrapper.ErrorRecord) error {
+	return errors.New("no space left on device")
+}
+
 func TestFavoriteNumHandler_Favorite_Success(t *testing.T) {
 	// Setup
 	e := echo.New()
@@ -39,8 +57,8 @@ func TestFavoriteNumHandler_Favorite_Success(t *testing.T) {
 
 	// Test
 	v := validator.New(validator.WithRequiredStructEnabled())
-	vw := validatorwrapper.NewValidatorWrapper(v)
-	h := NewFavoriteNumHandler(vw)
+	vw := validatorwrapper.NewValidatorWrapper(v, validatorwrapper.NewNopSink(), testLogger)
+	h := NewFavoriteNumHandler(vw, store.NewMemoryFavoriteRepo(), testLogger)
 	err := h.Favorite(c)
 
 	// Assert
@@ -64,8 +82,8 @@ func TestFavoriteNumHandler_Favorite_InvalidJSON(t *testing.T) {
 
 	// Test
 	v := validator.New(validator.WithRequiredStructEnabled())
-	vw := validatorwrapper.NewValidatorWrapper(v)
-	h := NewFavoriteNumHandler(vw)
+	vw := validatorwrapper.NewValidatorWrapper(v, validatorwrapper.NewNopSink(), testLogger)
+	h := NewFavoriteNumHandler(vw, store.NewMemoryFavoriteRepo(), testLogger)
 	err := h.Favorite(c)
 
 	// Assert
@@ -92,8 +110,8 @@ func TestFavoriteNumHandler_Favorite_MissingUserID(t *testing.T) {
 
 	// Test
 	v := validator.New(validator.WithRequiredStructEnabled())
-	vw := validatorwrapper.NewValidatorWrapper(v)
-	h := NewFavoriteNumHandler(vw)
+	vw := validatorwrapper.NewValidatorWrapper(v, validatorwrapper.NewNopSink(), testLogger)
+	h := NewFavoriteNumHandler(vw, store.NewMemoryFavoriteRepo(), testLogger)
 	err := h.Favorite(c)
 
 	// Assert
@@ -121,8 +139,8 @@ func TestFavoriteNumHandler_Favorite_InvalidUUID(t *testing.T) {
 
 	// Test
 	v := validator.New(validator.WithRequiredStructEnabled())
-	vw := validatorwrapper.NewValidatorWrapper(v)
-	h := NewFavoriteNumHandler(vw)
+	vw := validatorwrapper.NewValidatorWrapper(v, validatorwrapper.NewNopSink(), testLogger)
+	h := NewFavoriteNumHandler(vw, store.NewMemoryFavoriteRepo(), testLogger)
 	err := h.Favorite(c)
 
 	// Assert
@@ -133,6 +151,9 @@ func TestFavoriteNumHandler_Favorite_InvalidUUID(t *testing.T) {
 	json.Unmarshal(rec.Body.Bytes(), &resp)
 	assert.False(t, resp.IsOK)
 	assert.Equal(t, badRequestNotValid, resp.Msg)
+	assert.Equal(t, []FieldError{
+		{Field: "userId", Code: CodeFieldInvalid, Tag: "uuid_rfc4122", Value: "not-a-uuid", Message: "userId must be a valid UUID"},
+	}, resp.Errors)
 }
 
 func TestFavoriteNumHandler_Favorite_MissingFavNum(t *testing.T) {
@@ -149,8 +170,8 @@ func TestFavoriteNumHandler_Favorite_MissingFavNum(t *testing.T) {
 
 	// Test
 	v := validator.New(validator.WithRequiredStructEnabled())
-	vw := validatorwrapper.NewValidatorWrapper(v)
-	h := NewFavoriteNumHandler(vw)
+	vw := validatorwrapper.NewValidatorWrapper(v, validatorwrapper.NewNopSink(), testLogger)
+	h := NewFavoriteNumHandler(vw, store.NewMemoryFavoriteRepo(), testLogger)
 	err := h.Favorite(c)
 
 	// Assert
@@ -178,8 +199,8 @@ func TestFavoriteNumHandler_Favorite_ZeroFavNum(t *testing.T) {
 
 	// Test
 	v := validator.New(validator.WithRequiredStructEnabled())
-	vw := validatorwrapper.NewValidatorWrapper(v)
-	h := NewFavoriteNumHandler(vw)
+	vw := validatorwrapper.NewValidatorWrapper(v, validatorwrapper.NewNopSink(), testLogger)
+	h := NewFavoriteNumHandler(vw, store.NewMemoryFavoriteRepo(), testLogger)
 	err := h.Favorite(c)
 
 	// Assert
@@ -207,8 +228,37 @@ func TestFavoriteNumHandler_Favorite_NegativeFavNum(t *testing.T) {
 
 	// Test
 	v := validator.New(validator.WithRequiredStructEnabled())
-	vw := validatorwrapper.NewValidatorWrapper(v)
-	h := NewFavoriteNumHandler(vw)
+	vw := validatorwrapper.NewValidatorWrapper(v, validatorwrapper.NewNopSink(), testLogger)
+	h := NewFavoriteNumHandler(vw, store.NewMemoryFavoriteRepo(), testLogger)
+	err := h.Favorite(c)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	var resp Response
+	json.Unmarshal(rec.Body.Bytes(), &resp)
+	assert.False(t, resp.IsOK)
+	assert.Equal(t, badRequestNotValid, resp.Msg)
+}
+
+func TestFavoriteNumHandler_Favorite_SinkFailureKeepsBadRequest(t *testing.T) {
+	// Setup
+	e := echo.New()
+	req := FavoriteNumRequest{
+		UserID: "not-a-uuid",
+		FavNum: 42,
+	}
+	reqBody, _ := json.Marshal(req)
+	httpReq := httptest.NewRequest(http.MethodPost, "/favorite", bytes.NewReader(reqBody))
+	httpReq.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httpReq, rec)
+
+	// Test - the sink fails but the request is still just invalid
+	v := validator.New(validator.WithRequiredStructEnabled())
+	vw := validatorwrapper.NewValidatorWrapper(v, failingSink{}, testLogger, validatorwrapper.WithSinkErrorHandler(func(error) {}))
+	h := NewFavoriteNumHandler(vw, store.NewMemoryFavoriteRepo(), testLogger)
 	err := h.Favorite(c)
 
 	// Assert
@@ -219,6 +269,7 @@ func TestFavoriteNumHandler_Favorite_NegativeFavNum(t *testing.T) {
 	json.Unmarshal(rec.Body.Bytes(), &resp)
 	assert.False(t, resp.IsOK)
 	assert.Equal(t, badRequestNotValid, resp.Msg)
+	assert.Equal(t, uint64(1), vw.SinkFailures())
 }
 
 func TestFavoriteNumHandler_Favorite_InternalError(t *testing.T) {
@@ -236,7 +287,7 @@ func TestFavoriteNumHandler_Favorite_InternalError(t *testing.T) {
 
 	// Test - Mock validator that returns a non-validation error
 	mockV := &mockValidator{err: errors.New("unexpected internal error")}
-	h := NewFavoriteNumHandler(mockV)
+	h := NewFavoriteNumHandler(mockV, store.NewMemoryFavoriteRepo(), testLogger)
 	err := h.Favorite(c)
 
 	// Assert
@@ -251,8 +302,74 @@ func TestFavoriteNumHandler_Favorite_InternalError(t *testing.T) {
 
 func TestNewFavoriteNumHandler(t *testing.T) {
 	v := validator.New(validator.WithRequiredStructEnabled())
-	vw := validatorwrapper.NewValidatorWrapper(v)
-	h := NewFavoriteNumHandler(vw)
+	vw := validatorwrapper.NewValidatorWrapper(v, validatorwrapper.NewNopSink(), testLogger)
+	h := NewFavoriteNumHandler(vw, store.NewMemoryFavoriteRepo(), testLogger)
 	assert.NotNil(t, h)
-	assert.NotNil(t, h.v)
+	assert.NotNil(t, h.handle)
+}
+
+func getFavorite(h *FavoriteNumHandler, userID string) (int, Response) {
+	e := echo.New()
+	httpReq := httptest.NewRequest(http.MethodGet, "/favorite/"+userID, nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httpReq, rec)
+	c.SetPath("/favorite/:userId")
+	c.SetParamNames("userId")
+	c.SetParamValues(userID)
+	h.GetFavorite(c)
+
+	var resp Response
+	json.Unmarshal(rec.Body.Bytes(), &resp)
+	return rec.Code, resp
+}
+
+func TestFavoriteNumHandler_GetFavorite_Saved(t *testing.T) {
+	// Setup - save a favorite number first
+	e := echo.New()
+	req := FavoriteNumRequest{
+		UserID: "550e8400-e29b-41d4-a716-446655440000",
+		FavNum: 42,
+	}
+	reqBody, _ := json.Marshal(req)
+	httpReq := httptest.NewRequest(http.MethodPost, "/favorite", bytes.NewReader(reqBody))
+	httpReq.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httpReq, rec)
+
+	v := validator.New(validator.WithRequiredStructEnabled())
+	vw := validatorwrapper.NewValidatorWrapper(v, validatorwrapper.NewNopSink(), testLogger)
+	h := NewFavoriteNumHandler(vw, store.NewMemoryFavoriteRepo(), testLogger)
+	assert.NoError(t, h.Favorite(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// Test
+	code, resp := getFavorite(h, req.UserID)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, code)
+	assert.True(t, resp.IsOK)
+	assert.Equal(t, map[string]any{"userId": req.UserID, "favNum": float64(42)}, resp.Data)
+}
+
+func TestFavoriteNumHandler_GetFavorite_NotFound(t *testing.T) {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	vw := validatorwrapper.NewValidatorWrapper(v, validatorwrapper.NewNopSink(), testLogger)
+	h := NewFavoriteNumHandler(vw, store.NewMemoryFavoriteRepo(), testLogger)
+
+	code, resp := getFavorite(h, "550e8400-e29b-41d4-a716-446655440000")
+
+	assert.Equal(t, http.StatusNotFound, code)
+	assert.False(t, resp.IsOK)
+	assert.Equal(t, notFound, resp.Msg)
+}
+
+func TestFavoriteNumHandler_GetFavorite_InvalidUUID(t *testing.T) {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	vw := validatorwrapper.NewValidatorWrapper(v, validatorwrapper.NewNopSink(), testLogger)
+	h := NewFavoriteNumHandler(vw, store.NewMemoryFavoriteRepo(), testLogger)
+
+	code, resp := getFavorite(h, "not-a-uuid")
+
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, badRequestNotValid, resp.Msg)
 }
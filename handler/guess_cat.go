@@ -1,63 +1,115 @@
 package handler
 
 import (
-	"errors"
-	"log"
-	"net/http"
+	"context"
+	"log/slog"
+	"strings"
 
-	"github.com/BoomNooB/medium-go-di/validatorwrapper"
 	"github.com/labstack/echo/v4"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// maxGuessAttempts is how many guesses a user gets, tracked server side.
+// The cap applies per userId, which the client chooses: a new userId gets
+// new attempts. The rate limit of the route is what bounds guesses per
+// client.
+const maxGuessAttempts = 3
+
+const (
+	guessCorrect       = "correct"
+	guessIncorrect     = "incorrect"
+	guessOutOfAttempts = "out_of_attempts"
+	// guessAlreadyWon answers every guess after a correct one, the game of
+	// the user is over and no attempt is used.
+	guessAlreadyWon = "already_won"
 )
 
 type GuessCatNameRequest struct {
 	GuessName string `json:"guessName" validate:"required,min=1,max=30"`
 	UserID    string `json:"userId" validate:"required,uuid_rfc4122"`
-	Attempts  int    `json:"attempts" validate:"required,gte=1,lte=3"`
+}
+
+type GuessCatNameResult struct {
+	Result       string `json:"result"`
+	AttemptsLeft int    `json:"attemptsLeft"`
+}
+
+// CatNameStore holds the name to guess and the attempts of every user.
+type CatNameStore interface {
+	CatName(ctx context.Context) (string, error)
+	// IncrementAttempts records a new attempt of userID and returns its
+	// total so far, and whether userID already won. Attempts are no longer
+	// counted once won. Stores may forget idle players to bound their memory.
+	IncrementAttempts(ctx context.Context, userID string) (int, bool, error)
+	// RecordWin ends the game of userID after a correct guess.
+	RecordWin(ctx context.Context, userID string) error
 }
 
 type GuessCatNameHandler struct {
-	v Valiator
+	store  CatNameStore
+	handle echo.HandlerFunc
 }
 
-func NewGuessCatNameHandler(validator Valiator) *GuessCatNameHandler {
-	return &GuessCatNameHandler{
-		v: validator,
+func NewGuessCatNameHandler(validator Valiator, store CatNameStore, logger *slog.Logger) *GuessCatNameHandler {
+	gh := &GuessCatNameHandler{
+		store: store,
 	}
+	gh.handle = ValidateEndpoint(validator, logger, gh.guess)
+	return gh
 }
 
 func (gh *GuessCatNameHandler) GuessTheCatName(c echo.Context) error {
-	ctx := c.Request().Context()
-	req := GuessCatNameRequest{}
-	err := c.Bind(&req)
+	return gh.handle(c)
+}
+
+func (gh *GuessCatNameHandler) guess(ctx context.Context, req *GuessCatNameRequest) (Response, error) {
+	attempts, won, err := gh.store.IncrementAttempts(ctx, req.UserID)
 	if err != nil {
-		return c.JSON(
-			http.StatusBadRequest,
-			newBadRequestResponse(badRequestJSONSyntax),
-		)
+		return Response{}, err
+	}
+	if won {
+		return newDataResponse(GuessCatNameResult{Result: guessAlreadyWon}), nil
+	}
+	if attempts > maxGuessAttempts {
+		return newDataResponse(GuessCatNameResult{Result: guessOutOfAttempts}), nil
 	}
 
-	err = gh.v.StructValidation(ctx, &req)
+	catName, err := gh.store.CatName(ctx)
 	if err != nil {
-		// check if it's a validation error or not
-		if errors.Is(err, validatorwrapper.ErrValidationFailed) {
-			return c.JSON(
-				http.StatusBadRequest,
-				newBadRequestResponse(badRequestNotValid),
-			)
-		}
+		return Response{}, err
+	}
 
-		// else it's an internal error
-		log.Printf("Guess Name: %s, User ID: %s, Attempts: %d\n", req.GuessName, req.UserID, req.Attempts)
-		log.Printf("Error: %v\n", err)
-		return c.JSON(
-			http.StatusInternalServerError,
-			newInternalErrorResponse(),
-		)
+	result := GuessCatNameResult{
+		Result:       guessIncorrect,
+		AttemptsLeft: maxGuessAttempts - attempts,
 	}
+	if normalizeName(req.GuessName) == normalizeName(catName) {
+		if err := gh.store.RecordWin(ctx, req.UserID); err != nil {
+			return Response{}, err
+		}
+		result.Result = guessCorrect
+	}
+	return newDataResponse(result), nil
+}
 
-	log.Println("Yay! Valid guess for cat name!")
-	return c.JSON(
-		http.StatusOK,
-		newOkResponse(),
+// normalizeName folds case and strips Latin accents so "MÍTTENS" matches
+// "mittens". Only the combining diacritics block is removed, Thai vowel and
+// tone marks are part of the name and are kept.
+func normalizeName(name string) string {
+	t := transform.Chain(
+		norm.NFD,
+		runes.Remove(runes.Predicate(func(r rune) bool {
+			return r >= 0x0300 && r <= 0x036F
+		})),
+		cases.Fold(),
+		norm.NFC,
 	)
+	out, _, err := transform.String(t, strings.TrimSpace(name))
+	if err != nil {
+		return strings.ToLower(strings.TrimSpace(name))
+	}
+	return out
 }
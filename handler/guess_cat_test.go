@@ -2,16 +2,19 @@ package handler
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/BoomNooB/medium-go-di/store"
 	"github.com/BoomNooB/medium-go-di/validatorwrapper"
 	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestGuessCatNameHandler_GuessTheCatName_Success(t *testing.T) {
@@ -20,7 +23,6 @@ func TestGuessCatNameHandler_GuessTheCatName_Success(t *testing.T) {
 	req := GuessCatNameRequest{
 		GuessName: "Fluffy",
 		UserID:    "550e8400-e29b-41d4-a716-446655440000",
-		Attempts:  2,
 	}
 	reqBody, _ := json.Marshal(req)
 	httpReq := httptest.NewRequest(http.MethodPost, "/guess-cat", bytes.NewReader(reqBody))
@@ -30,8 +32,8 @@ func TestGuessCatNameHandler_GuessTheCatName_Success(t *testing.T) {
 
 	// Test
 	v := validator.New(validator.WithRequiredStructEnabled())
-	vw := validatorwrapper.NewValidatorWrapper(v)
-	h := NewGuessCatNameHandler(vw)
+	vw := validatorwrapper.NewValidatorWrapper(v, validatorwrapper.NewNopSink(), testLogger)
+	h := NewGuessCatNameHandler(vw, store.NewMemoryCatNameStore("Mittens"), testLogger)
 	err := h.GuessTheCatName(c)
 
 	// Assert
@@ -55,8 +57,8 @@ func TestGuessCatNameHandler_GuessTheCatName_InvalidJSON(t *testing.T) {
 
 	// Test
 	v := validator.New(validator.WithRequiredStructEnabled())
-	vw := validatorwrapper.NewValidatorWrapper(v)
-	h := NewGuessCatNameHandler(vw)
+	vw := validatorwrapper.NewValidatorWrapper(v, validatorwrapper.NewNopSink(), testLogger)
+	h := NewGuessCatNameHandler(vw, store.NewMemoryCatNameStore("Mittens"), testLogger)
 	err := h.GuessTheCatName(c)
 
 	// Assert
@@ -73,8 +75,7 @@ func TestGuessCatNameHandler_GuessTheCatName_MissingGuessName(t *testing.T) {
 	// Setup
 	e := echo.New()
 	req := GuessCatNameRequest{
-		UserID:   "550e8400-e29b-41d4-a716-446655440000",
-		Attempts: 2,
+		UserID: "550e8400-e29b-41d4-a716-446655440000",
 	}
 	reqBody, _ := json.Marshal(req)
 	httpReq := httptest.NewRequest(http.MethodPost, "/guess-cat", bytes.NewReader(reqBody))
@@ -84,8 +85,8 @@ func TestGuessCatNameHandler_GuessTheCatName_MissingGuessName(t *testing.T) {
 
 	// Test
 	v := validator.New(validator.WithRequiredStructEnabled())
-	vw := validatorwrapper.NewValidatorWrapper(v)
-	h := NewGuessCatNameHandler(vw)
+	vw := validatorwrapper.NewValidatorWrapper(v, validatorwrapper.NewNopSink(), testLogger)
+	h := NewGuessCatNameHandler(vw, store.NewMemoryCatNameStore("Mittens"), testLogger)
 	err := h.GuessTheCatName(c)
 
 	// Assert
@@ -104,7 +105,6 @@ func TestGuessCatNameHandler_GuessTheCatName_NameTooLong(t *testing.T) {
 	req := GuessCatNameRequest{
 		GuessName: "ThisIsAReallyLongCatNameThatExceedsTheMaximumLength",
 		UserID:    "550e8400-e29b-41d4-a716-446655440000",
-		Attempts:  2,
 	}
 	reqBody, _ := json.Marshal(req)
 	httpReq := httptest.NewRequest(http.MethodPost, "/guess-cat", bytes.NewReader(reqBody))
@@ -114,8 +114,8 @@ func TestGuessCatNameHandler_GuessTheCatName_NameTooLong(t *testing.T) {
 
 	// Test
 	v := validator.New(validator.WithRequiredStructEnabled())
-	vw := validatorwrapper.NewValidatorWrapper(v)
-	h := NewGuessCatNameHandler(vw)
+	vw := validatorwrapper.NewValidatorWrapper(v, validatorwrapper.NewNopSink(), testLogger)
+	h := NewGuessCatNameHandler(vw, store.NewMemoryCatNameStore("Mittens"), testLogger)
 	err := h.GuessTheCatName(c)
 
 	// Assert
@@ -134,7 +134,6 @@ func TestGuessCatNameHandler_GuessTheCatName_InvalidUserID(t *testing.T) {
 	req := GuessCatNameRequest{
 		GuessName: "Fluffy",
 		UserID:    "not-a-uuid",
-		Attempts:  2,
 	}
 	reqBody, _ := json.Marshal(req)
 	httpReq := httptest.NewRequest(http.MethodPost, "/guess-cat", bytes.NewReader(reqBody))
@@ -144,8 +143,8 @@ func TestGuessCatNameHandler_GuessTheCatName_InvalidUserID(t *testing.T) {
 
 	// Test
 	v := validator.New(validator.WithRequiredStructEnabled())
-	vw := validatorwrapper.NewValidatorWrapper(v)
-	h := NewGuessCatNameHandler(vw)
+	vw := validatorwrapper.NewValidatorWrapper(v, validatorwrapper.NewNopSink(), testLogger)
+	h := NewGuessCatNameHandler(vw, store.NewMemoryCatNameStore("Mittens"), testLogger)
 	err := h.GuessTheCatName(c)
 
 	// Assert
@@ -158,64 +157,121 @@ func TestGuessCatNameHandler_GuessTheCatName_InvalidUserID(t *testing.T) {
 	assert.Equal(t, badRequestNotValid, resp.Msg)
 }
 
-func TestGuessCatNameHandler_GuessTheCatName_AttemptsZero(t *testing.T) {
-	// Setup
+// failingCatNameStore simulates a store that cannot be reached
+type failingCatNameStore struct{}
+
+func (failingCatNameStore) CatName(ctx context.Context) (string, error) {
+	return "", errors.New("store unavailable")
+}
+
+func (failingCatNameStore) IncrementAttempts(ctx context.Context, userID string) (int, bool, error) {
+	return 0, false, errors.New("store unavailable")
+}
+
+func (failingCatNameStore) RecordWin(ctx context.Context, userID string) error {
+	return errors.New("store unavailable")
+}
+
+func guessCat(h *GuessCatNameHandler, guessName, userID string) (int, GuessCatNameResult) {
 	e := echo.New()
-	req := GuessCatNameRequest{
-		GuessName: "Fluffy",
-		UserID:    "550e8400-e29b-41d4-a716-446655440000",
-		Attempts:  0,
-	}
-	reqBody, _ := json.Marshal(req)
+	reqBody, _ := json.Marshal(GuessCatNameRequest{GuessName: guessName, UserID: userID})
 	httpReq := httptest.NewRequest(http.MethodPost, "/guess-cat", bytes.NewReader(reqBody))
 	httpReq.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 	rec := httptest.NewRecorder()
 	c := e.NewContext(httpReq, rec)
+	h.GuessTheCatName(c)
 
-	// Test
-	v := validator.New(validator.WithRequiredStructEnabled())
-	vw := validatorwrapper.NewValidatorWrapper(v)
-	h := NewGuessCatNameHandler(vw)
-	err := h.GuessTheCatName(c)
+	var resp struct {
+		IsOK bool               `json:"isOK"`
+		Data GuessCatNameResult `json:"data"`
+	}
+	json.Unmarshal(rec.Body.Bytes(), &resp)
+	return rec.Code, resp.Data
+}
 
-	// Assert
-	assert.NoError(t, err)
-	assert.Equal(t, http.StatusBadRequest, rec.Code)
+func TestGuessCatNameHandler_GuessTheCatName_Results(t *testing.T) {
+	tests := []struct {
+		name      string
+		guessName string
+		want      string
+	}{
+		{name: "exact", guessName: "Mittens", want: guessCorrect},
+		{name: "case insensitive", guessName: "mITTENS", want: guessCorrect},
+		{name: "accent insensitive", guessName: "Mítténs", want: guessCorrect},
+		{name: "surrounding spaces", guessName: " Mittens ", want: guessCorrect},
+		{name: "wrong", guessName: "Fluffy", want: guessIncorrect},
+	}
 
-	var resp Response
-	json.Unmarshal(rec.Body.Bytes(), &resp)
-	assert.False(t, resp.IsOK)
-	assert.Equal(t, badRequestNotValid, resp.Msg)
+	v := validator.New(validator.WithRequiredStructEnabled())
+	vw := validatorwrapper.NewValidatorWrapper(v, validatorwrapper.NewNopSink(), testLogger)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewGuessCatNameHandler(vw, store.NewMemoryCatNameStore("Mittens"), testLogger)
+			code, result := guessCat(h, tt.guessName, "550e8400-e29b-41d4-a716-446655440000")
+			assert.Equal(t, http.StatusOK, code)
+			assert.Equal(t, tt.want, result.Result)
+			assert.Equal(t, maxGuessAttempts-1, result.AttemptsLeft)
+		})
+	}
 }
 
-func TestGuessCatNameHandler_GuessTheCatName_AttemptsExceedsMax(t *testing.T) {
-	// Setup
-	e := echo.New()
-	req := GuessCatNameRequest{
-		GuessName: "Fluffy",
-		UserID:    "550e8400-e29b-41d4-a716-446655440000",
-		Attempts:  5,
+func TestGuessCatNameHandler_GuessTheCatName_OutOfAttempts(t *testing.T) {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	vw := validatorwrapper.NewValidatorWrapper(v, validatorwrapper.NewNopSink(), testLogger)
+	h := NewGuessCatNameHandler(vw, store.NewMemoryCatNameStore("Mittens"), testLogger)
+	userID := "550e8400-e29b-41d4-a716-446655440000"
+
+	for i := 1; i <= maxGuessAttempts; i++ {
+		code, result := guessCat(h, "Fluffy", userID)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, guessIncorrect, result.Result)
+		assert.Equal(t, maxGuessAttempts-i, result.AttemptsLeft)
 	}
-	reqBody, _ := json.Marshal(req)
-	httpReq := httptest.NewRequest(http.MethodPost, "/guess-cat", bytes.NewReader(reqBody))
-	httpReq.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
-	rec := httptest.NewRecorder()
-	c := e.NewContext(httpReq, rec)
 
-	// Test
+	// even the right answer is refused once attempts are used up
+	code, result := guessCat(h, "Mittens", userID)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, guessOutOfAttempts, result.Result)
+	assert.Zero(t, result.AttemptsLeft)
+
+	// other users are not affected
+	_, result = guessCat(h, "Mittens", "a8836583-59ee-4bf8-8fa7-9013af8459ae")
+	assert.Equal(t, guessCorrect, result.Result)
+}
+
+func TestGuessCatNameHandler_GuessTheCatName_AlreadyWon(t *testing.T) {
 	v := validator.New(validator.WithRequiredStructEnabled())
-	vw := validatorwrapper.NewValidatorWrapper(v)
-	h := NewGuessCatNameHandler(vw)
-	err := h.GuessTheCatName(c)
+	vw := validatorwrapper.NewValidatorWrapper(v, validatorwrapper.NewNopSink(), testLogger)
+	catNames := store.NewMemoryCatNameStore("Mittens")
+	h := NewGuessCatNameHandler(vw, catNames, testLogger)
+	userID := "550e8400-e29b-41d4-a716-446655440000"
+
+	_, result := guessCat(h, "Fluffy", userID)
+	assert.Equal(t, guessIncorrect, result.Result)
+	_, result = guessCat(h, "Mittens", userID)
+	assert.Equal(t, guessCorrect, result.Result)
+
+	// the game is over, right or wrong, and no attempt is used
+	for _, guessName := range []string{"Mittens", "Fluffy", "Mittens"} {
+		code, result := guessCat(h, guessName, userID)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, guessAlreadyWon, result.Result)
+		assert.Zero(t, result.AttemptsLeft)
+	}
+	attempts, won, err := catNames.IncrementAttempts(context.Background(), userID)
+	require.NoError(t, err)
+	assert.True(t, won)
+	assert.Equal(t, 2, attempts)
+}
 
-	// Assert
-	assert.NoError(t, err)
-	assert.Equal(t, http.StatusBadRequest, rec.Code)
+func TestGuessCatNameHandler_GuessTheCatName_StoreError(t *testing.T) {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	vw := validatorwrapper.NewValidatorWrapper(v, validatorwrapper.NewNopSink(), testLogger)
+	h := NewGuessCatNameHandler(vw, failingCatNameStore{}, testLogger)
 
-	var resp Response
-	json.Unmarshal(rec.Body.Bytes(), &resp)
-	assert.False(t, resp.IsOK)
-	assert.Equal(t, badRequestNotValid, resp.Msg)
+	code, _ := guessCat(h, "Mittens", "550e8400-e29b-41d4-a716-446655440000")
+	assert.Equal(t, http.StatusInternalServerError, code)
 }
 
 func TestGuessCatNameHandler_GuessTheCatName_InternalError(t *testing.T) {
@@ -224,7 +280,6 @@ func TestGuessCatNameHandler_GuessTheCatName_InternalError(t *testing.T) {
 	req := GuessCatNameRequest{
 		GuessName: "Fluffy",
 		UserID:    "550e8400-e29b-41d4-a716-446655440000",
-		Attempts:  2,
 	}
 	reqBody, _ := json.Marshal(req)
 	httpReq := httptest.NewRequest(http.MethodPost, "/guess-cat", bytes.NewReader(reqBody))
@@ -234,7 +289,7 @@ func TestGuessCatNameHandler_GuessTheCatName_InternalError(t *testing.T) {
 
 	// Test - Mock validator that returns a non-validation error
 	mockV := &mockValidator{err: errors.New("unexpected internal error")}
-	h := NewGuessCatNameHandler(mockV)
+	h := NewGuessCatNameHandler(mockV, store.NewMemoryCatNameStore("Mittens"), testLogger)
 	err := h.GuessTheCatName(c)
 
 	// Assert
@@ -249,8 +304,8 @@ func TestGuessCatNameHandler_GuessTheCatName_InternalError(t *testing.T) {
 
 func TestNewGuessCatNameHandler(t *testing.T) {
 	v := validator.New(validator.WithRequiredStructEnabled())
-	vw := validatorwrapper.NewValidatorWrapper(v)
-	h := NewGuessCatNameHandler(vw)
+	vw := validatorwrapper.NewValidatorWrapper(v, validatorwrapper.NewNopSink(), testLogger)
+	h := NewGuessCatNameHandler(vw, store.NewMemoryCatNameStore("Mittens"), testLogger)
 	assert.NotNil(t, h)
-	assert.NotNil(t, h.v)
+	assert.NotNil(t, h.handle)
 }
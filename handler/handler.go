@@ -1,14 +1,34 @@
 package handler
 
-import "context"
+import (
+	"context"
+	"errors"
+
+	"github.com/BoomNooB/medium-go-di/validatorwrapper"
+)
 
 type Valiator interface {
 	StructValidation(ctx context.Context, req any) error
 }
 
 type Response struct {
-	IsOK bool   `json:"isOK"`
-	Msg  string `json:"msg,omitempty"`
+	IsOK bool `json:"isOK"`
+	// Code is set on every failed response, see ErrorCode.
+	Code   ErrorCode    `json:"code,omitempty"`
+	Msg    string       `json:"msg,omitempty"`
+	Data   any          `json:"data,omitempty"`
+	Errors []FieldError `json:"errors,omitempty"`
+	// RequestID is set on failed responses to quote when reporting them.
+	RequestID string `json:"requestId,omitempty"`
+}
+
+type FieldError struct {
+	Field   string    `json:"field"`
+	Code    ErrorCode `json:"code"`
+	Tag     string    `json:"tag"`
+	Param   string    `json:"param,omitempty"`
+	Value   any       `json:"value,omitempty"`
+	Message string    `json:"message,omitempty"`
 }
 
 func newOkResponse() Response {
@@ -17,21 +37,61 @@ func newOkResponse() Response {
 	}
 }
 
+func newDataResponse(data any) Response {
+	return Response{
+		IsOK: true,
+		Data: data,
+	}
+}
+
 const (
 	badRequestJSONSyntax = "json not valid"
 	badRequestNotValid   = "request not valid"
+	badRequestParams     = "path or query parameters not valid"
+	notFound             = "not found"
 )
 
-func newBadRequestResponse(msg string) Response {
+func newBadRequestResponse(code ErrorCode, msg string) Response {
 	return Response{
 		IsOK: false,
+		Code: code,
 		Msg:  msg,
 	}
 }
 
+// newValidationFailedResponse builds the bad request response and attaches
+// the failing fields when the validator reported them.
+func newValidationFailedResponse(err error) Response {
+	resp := newBadRequestResponse(CodeValidationFailed, badRequestNotValid)
+	var vErr *validatorwrapper.ValidationError
+	if errors.As(err, &vErr) {
+		resp.Errors = make([]FieldError, 0, len(vErr.Fields))
+		for _, f := range vErr.Fields {
+			resp.Errors = append(resp.Errors, FieldError{
+				Field:   f.Field,
+				Code:    fieldErrorCode(f.Tag),
+				Tag:     f.Tag,
+				Param:   f.Param,
+				Value:   f.Value,
+				Message: f.Message,
+			})
+		}
+	}
+	return resp
+}
+
+func newNotFoundResponse() Response {
+	return Response{
+		IsOK: false,
+		Code: CodeNotFound,
+		Msg:  notFound,
+	}
+}
+
 func newInternalErrorResponse() Response {
 	return Response{
 		IsOK: false,
+		Code: CodeInternal,
 		Msg:  "internal server error",
 	}
 }
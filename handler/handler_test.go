@@ -1,11 +1,16 @@
 package handler
 
 import (
+	"log/slog"
 	"testing"
 
+	"github.com/BoomNooB/medium-go-di/validatorwrapper"
 	"github.com/stretchr/testify/assert"
 )
 
+// testLogger drops everything so test output stays readable
+var testLogger = slog.New(slog.DiscardHandler)
+
 func TestNewOkResponse(t *testing.T) {
 	resp := newOkResponse()
 	assert.True(t, resp.IsOK)
@@ -14,13 +19,39 @@ func TestNewOkResponse(t *testing.T) {
 
 func TestNewBadRequestResponse(t *testing.T) {
 	msg := "test error message"
-	resp := newBadRequestResponse(msg)
+	resp := newBadRequestResponse(CodeJSONSyntax, msg)
 	assert.False(t, resp.IsOK)
+	assert.Equal(t, CodeJSONSyntax, resp.Code)
 	assert.Equal(t, msg, resp.Msg)
 }
 
 func TestNewInternalErrorResponse(t *testing.T) {
 	resp := newInternalErrorResponse()
 	assert.False(t, resp.IsOK)
+	assert.Equal(t, CodeInternal, resp.Code)
 	assert.Equal(t, "internal server error", resp.Msg)
 }
+
+func TestNewValidationFailedResponse(t *testing.T) {
+	err := &validatorwrapper.ValidationError{
+		Fields: []validatorwrapper.FieldError{
+			{Field: "favNum", Tag: "gt", Param: "0", Value: -5},
+			{Field: "userId", Tag: "required"},
+		},
+	}
+	resp := newValidationFailedResponse(err)
+	assert.False(t, resp.IsOK)
+	assert.Equal(t, CodeValidationFailed, resp.Code)
+	assert.Equal(t, badRequestNotValid, resp.Msg)
+	assert.Equal(t, []FieldError{
+		{Field: "favNum", Code: CodeFieldInvalid, Tag: "gt", Param: "0", Value: -5},
+		{Field: "userId", Code: CodeFieldRequired, Tag: "required"},
+	}, resp.Errors)
+}
+
+func TestNewValidationFailedResponse_SentinelOnly(t *testing.T) {
+	resp := newValidationFailedResponse(validatorwrapper.ErrValidationFailed)
+	assert.False(t, resp.IsOK)
+	assert.Equal(t, badRequestNotValid, resp.Msg)
+	assert.Empty(t, resp.Errors)
+}
@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sort"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	notReady = "not ready"
+	// checkUnavailable replaces the error of a failing check in the public
+	// answer, errors can hold paths and driver details. They are logged.
+	checkUnavailable = "unavailable"
+)
+
+// Pinger is a dependency the readiness probe checks, such as the validation
+// error sink or the database.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// BuildInfo describes the running binary. It is filled from ldflags at
+// build time.
+type BuildInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"buildTime"`
+	GoVersion string `json:"goVersion"`
+}
+
+type HealthHandler struct {
+	checks map[string]Pinger
+	build  BuildInfo
+	logger *slog.Logger
+}
+
+// NewHealthHandler serves the liveness, readiness and version endpoints.
+// checks maps a dependency name to the Pinger used by the readiness probe.
+func NewHealthHandler(checks map[string]Pinger, build BuildInfo, logger *slog.Logger) *HealthHandler {
+	return &HealthHandler{
+		checks: checks,
+		build:  build,
+		logger: logger,
+	}
+}
+
+// Healthz answers as long as the process is able to serve requests.
+func (hh *HealthHandler) Healthz(c echo.Context) error {
+	return c.JSON(http.StatusOK, newOkResponse())
+}
+
+// Readyz pings every dependency and answers 503 when one of them fails, with
+// the state of each dependency in data: "ok" or "unavailable".
+func (hh *HealthHandler) Readyz(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	names := make([]string, 0, len(hh.checks))
+	for name := range hh.checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	ready := true
+	status := make(map[string]string, len(hh.checks))
+	for _, name := range names {
+		if err := hh.checks[name].Ping(ctx); err != nil {
+			hh.logger.WarnContext(ctx, "dependency not ready", "dependency", name, "error", err)
+			status[name] = checkUnavailable
+			ready = false
+			continue
+		}
+		status[name] = "ok"
+	}
+
+	if !ready {
+		return respond(c, http.StatusServiceUnavailable, Response{
+			IsOK: false,
+			Code: CodeNotReady,
+			Msg:  notReady,
+			Data: status,
+		})
+	}
+	return c.JSON(http.StatusOK, newDataResponse(status))
+}
+
+// Version returns the build information of the binary.
+func (hh *HealthHandler) Version(c echo.Context) error {
+	return c.JSON(http.StatusOK, newDataResponse(hh.build))
+}
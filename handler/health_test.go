@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type pingerFunc func(ctx context.Context) error
+
+func (f pingerFunc) Ping(ctx context.Context) error {
+	return f(ctx)
+}
+
+var okPinger = pingerFunc(func(ctx context.Context) error { return nil })
+
+func serveHealth(t *testing.T, h *HealthHandler, fn func(*HealthHandler, echo.Context) error) (*httptest.ResponseRecorder, Response) {
+	t.Helper()
+	e := echo.New()
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+	require.NoError(t, fn(h, c))
+
+	var resp Response
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	return rec, resp
+}
+
+func TestHealthHandler_Healthz(t *testing.T) {
+	// Setup
+	h := NewHealthHandler(nil, BuildInfo{}, testLogger)
+
+	// Test
+	rec, resp := serveHealth(t, h, (*HealthHandler).Healthz)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, resp.IsOK)
+}
+
+func TestHealthHandler_Readyz(t *testing.T) {
+	// Setup
+	h := NewHealthHandler(map[string]Pinger{
+		"validation_sink": okPinger,
+		"storage":         okPinger,
+	}, BuildInfo{}, testLogger)
+
+	// Test
+	rec, resp := serveHealth(t, h, (*HealthHandler).Readyz)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, resp.IsOK)
+	assert.Equal(t, map[string]any{"validation_sink": "ok", "storage": "ok"}, resp.Data)
+}
+
+func TestHealthHandler_Readyz_NotReady(t *testing.T) {
+	// Setup
+	h := NewHealthHandler(map[string]Pinger{
+		"validation_sink": pingerFunc(func(ctx context.Context) error {
+			return errors.New("open /var/lib/app/validation_errors.csv: permission denied")
+		}),
+		"storage": okPinger,
+	}, BuildInfo{}, testLogger)
+
+	// Test
+	rec, resp := serveHealth(t, h, (*HealthHandler).Readyz)
+
+	// Assert
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.False(t, resp.IsOK)
+	assert.Equal(t, notReady, resp.Msg)
+	assert.Equal(t, map[string]any{"validation_sink": checkUnavailable, "storage": "ok"}, resp.Data)
+	assert.NotContains(t, rec.Body.String(), "/var/lib/app")
+}
+
+func TestHealthHandler_Version(t *testing.T) {
+	// Setup
+	build := BuildInfo{Version: "v1.2.3", Commit: "abc123", BuildTime: "2025-01-02T03:04:05Z", GoVersion: "go1.25.0"}
+	h := NewHealthHandler(nil, build, testLogger)
+
+	// Test
+	rec, resp := serveHealth(t, h, (*HealthHandler).Version)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, map[string]any{
+		"version":   "v1.2.3",
+		"commit":    "abc123",
+		"buildTime": "2025-01-02T03:04:05Z",
+		"goVersion": "go1.25.0",
+	}, resp.Data)
+}
@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/BoomNooB/medium-go-di/requestid"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+// RequestLogger writes one structured log line per request with its ID,
+// route, status and latency. It reads the status once written, so it runs
+// around WriteErrors.
+func RequestLogger(logger *slog.Logger) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			err := next(c)
+
+			req := c.Request()
+			res := c.Response()
+			requestID := requestid.FromContext(req.Context())
+			if requestID == "" {
+				requestID = req.Header.Get(echo.HeaderXRequestID)
+			}
+
+			level := slog.LevelInfo
+			if res.Status >= 500 {
+				level = slog.LevelError
+			}
+			logger.Log(req.Context(), level, "request",
+				"request_id", requestID,
+				"method", req.Method,
+				"route", c.Path(),
+				"status", res.Status,
+				"latency_ms", float64(time.Since(start).Microseconds())/1000,
+				"remote_ip", c.RealIP(),
+			)
+			return err
+		}
+	}
+}
+
+// Recover answers 500 to handlers that panic. The panic and the stack of
+// its goroutine are logged with logger, as one JSON line like the others.
+func Recover(logger *slog.Logger) echo.MiddlewareFunc {
+	return middleware.RecoverWithConfig(middleware.RecoverConfig{
+		DisableStackAll: true,
+		LogErrorFunc: func(c echo.Context, err error, stack []byte) error {
+			logger.ErrorContext(c.Request().Context(), "panic recovered",
+				"route", c.Path(),
+				"error", err,
+				"stack", string(stack),
+			)
+			return err
+		},
+	})
+}
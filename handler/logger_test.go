@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	e := echo.New()
+	e.Use(RequestLogger(logger), WriteErrors())
+	e.POST("/api/v1/favorite", func(c echo.Context) error {
+		return c.JSON(http.StatusBadRequest, newBadRequestResponse(CodeValidationFailed, badRequestNotValid))
+	})
+
+	httpReq := httptest.NewRequest(http.MethodPost, "/api/v1/favorite", nil)
+	httpReq.Header.Set(echo.HeaderXRequestID, "req-123")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httpReq)
+
+	var line map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+	assert.Equal(t, "request", line["msg"])
+	assert.Equal(t, "req-123", line["request_id"])
+	assert.Equal(t, "/api/v1/favorite", line["route"])
+	assert.Equal(t, float64(http.StatusBadRequest), line["status"])
+	assert.Contains(t, line, "latency_ms")
+}
+
+func TestRequestLogger_HandlerError(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	e := echo.New()
+	e.Use(RequestLogger(logger), WriteErrors())
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/missing", nil))
+
+	var line map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, float64(http.StatusNotFound), line["status"])
+}
+
+func TestRecover(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	e := echo.New()
+	e.Use(Recover(logger))
+	e.GET("/panic", func(c echo.Context) error {
+		panic("boom")
+	})
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/panic", nil))
+
+	var line map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Equal(t, "panic recovered", line["msg"])
+	assert.Equal(t, "/panic", line["route"])
+	assert.Equal(t, "boom", line["error"])
+	assert.Contains(t, line["stack"], "TestRecover")
+}
+
+func TestRequestLogger_Panic(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	// same order as the default config, recover inside the request logger
+	e := echo.New()
+	e.Use(RequestLogger(logger), Recover(slog.New(slog.DiscardHandler)), WriteErrors())
+	e.GET("/panic", func(c echo.Context) error {
+		panic("boom")
+	})
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/panic", nil))
+
+	var line map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Equal(t, "request", line["msg"])
+	assert.Equal(t, "ERROR", line["level"])
+	assert.Equal(t, float64(http.StatusInternalServerError), line["status"])
+}
@@ -1,11 +1,9 @@
 package handler
 
 import (
-	"errors"
-	"log"
-	"net/http"
+	"context"
+	"log/slog"
 
-	"github.com/BoomNooB/medium-go-di/validatorwrapper"
 	"github.com/labstack/echo/v4"
 )
 
@@ -14,49 +12,56 @@ type PetNameRequest struct {
 	OwnerID string `json:"ownerId" validate:"required,uuid_rfc4122"`
 }
 
+type ListPetsRequest struct {
+	OwnerID string `param:"ownerId" validate:"required,uuid_rfc4122"`
+}
+
+type ListPetsResult struct {
+	OwnerID  string   `json:"ownerId"`
+	PetNames []string `json:"petNames"`
+}
+
+// PetRepo stores the pet names of each owner.
+type PetRepo interface {
+	// AddPet ignores a name the owner already has.
+	AddPet(ctx context.Context, ownerID, petName string) error
+	ListPets(ctx context.Context, ownerID string) ([]string, error)
+}
+
 type PetNameHandler struct {
-	v Valiator
+	repo   PetRepo
+	handle echo.HandlerFunc
+	list   echo.HandlerFunc
 }
 
-func NewPetNameHandler(validator Valiator) *PetNameHandler {
-	return &PetNameHandler{
-		v: validator,
+func NewPetNameHandler(validator Valiator, repo PetRepo, logger *slog.Logger) *PetNameHandler {
+	ph := &PetNameHandler{
+		repo: repo,
 	}
+	ph.handle = ValidateEndpoint(validator, logger, ph.add)
+	ph.list = ValidateEndpoint(validator, logger, ph.listPets)
+	return ph
 }
 
 func (ph *PetNameHandler) ValidatePetName(c echo.Context) error {
-	ctx := c.Request().Context()
-	req := PetNameRequest{}
-	err := c.Bind(&req)
-	if err != nil {
-		return c.JSON(
-			http.StatusBadRequest,
-			newBadRequestResponse(badRequestJSONSyntax),
-		)
+	return ph.handle(c)
+}
+
+func (ph *PetNameHandler) ListPets(c echo.Context) error {
+	return ph.list(c)
+}
+
+func (ph *PetNameHandler) add(ctx context.Context, req *PetNameRequest) (Response, error) {
+	if err := ph.repo.AddPet(ctx, req.OwnerID, req.PetName); err != nil {
+		return Response{}, err
 	}
+	return newOkResponse(), nil
+}
 
-	err = ph.v.StructValidation(ctx, &req)
+func (ph *PetNameHandler) listPets(ctx context.Context, req *ListPetsRequest) (Response, error) {
+	pets, err := ph.repo.ListPets(ctx, req.OwnerID)
 	if err != nil {
-		// check if it's a validation error or not
-		if errors.Is(err, validatorwrapper.ErrValidationFailed) {
-			return c.JSON(
-				http.StatusBadRequest,
-				newBadRequestResponse(badRequestNotValid),
-			)
-		}
-
-		// else it's an internal error
-		log.Printf("Pet Name: %s, Owner ID: %s\n", req.PetName, req.OwnerID)
-		log.Printf("Error: %v\n", err)
-		return c.JSON(
-			http.StatusInternalServerError,
-			newInternalErrorResponse(),
-		)
+		return Response{}, err
 	}
-
-	log.Println("Yay! Pet name is valid!")
-	return c.JSON(
-		http.StatusOK,
-		newOkResponse(),
-	)
+	return newDataResponse(ListPetsResult{OwnerID: req.OwnerID, PetNames: pets}), nil
 }
@@ -8,6 +8,7 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/BoomNooB/medium-go-di/store"
 	"github.com/BoomNooB/medium-go-di/validatorwrapper"
 	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
@@ -29,8 +30,8 @@ func TestPetNameHandler_ValidatePetName_Success(t *testing.T) {
 
 	// Test
 	v := validator.New(validator.WithRequiredStructEnabled())
-	vw := validatorwrapper.NewValidatorWrapper(v)
-	h := NewPetNameHandler(vw)
+	vw := validatorwrapper.NewValidatorWrapper(v, validatorwrapper.NewNopSink(), testLogger)
+	h := NewPetNameHandler(vw, store.NewMemoryPetRepo(), testLogger)
 	err := h.ValidatePetName(c)
 
 	// Assert
@@ -54,8 +55,8 @@ func TestPetNameHandler_ValidatePetName_InvalidJSON(t *testing.T) {
 
 	// Test
 	v := validator.New(validator.WithRequiredStructEnabled())
-	vw := validatorwrapper.NewValidatorWrapper(v)
-	h := NewPetNameHandler(vw)
+	vw := validatorwrapper.NewValidatorWrapper(v, validatorwrapper.NewNopSink(), testLogger)
+	h := NewPetNameHandler(vw, store.NewMemoryPetRepo(), testLogger)
 	err := h.ValidatePetName(c)
 
 	// Assert
@@ -82,8 +83,8 @@ func TestPetNameHandler_ValidatePetName_MissingPetName(t *testing.T) {
 
 	// Test
 	v := validator.New(validator.WithRequiredStructEnabled())
-	vw := validatorwrapper.NewValidatorWrapper(v)
-	h := NewPetNameHandler(vw)
+	vw := validatorwrapper.NewValidatorWrapper(v, validatorwrapper.NewNopSink(), testLogger)
+	h := NewPetNameHandler(vw, store.NewMemoryPetRepo(), testLogger)
 	err := h.ValidatePetName(c)
 
 	// Assert
@@ -111,8 +112,8 @@ func TestPetNameHandler_ValidatePetName_NameTooShort(t *testing.T) {
 
 	// Test
 	v := validator.New(validator.WithRequiredStructEnabled())
-	vw := validatorwrapper.NewValidatorWrapper(v)
-	h := NewPetNameHandler(vw)
+	vw := validatorwrapper.NewValidatorWrapper(v, validatorwrapper.NewNopSink(), testLogger)
+	h := NewPetNameHandler(vw, store.NewMemoryPetRepo(), testLogger)
 	err := h.ValidatePetName(c)
 
 	// Assert
@@ -140,8 +141,8 @@ func TestPetNameHandler_ValidatePetName_NameTooLong(t *testing.T) {
 
 	// Test
 	v := validator.New(validator.WithRequiredStructEnabled())
-	vw := validatorwrapper.NewValidatorWrapper(v)
-	h := NewPetNameHandler(vw)
+	vw := validatorwrapper.NewValidatorWrapper(v, validatorwrapper.NewNopSink(), testLogger)
+	h := NewPetNameHandler(vw, store.NewMemoryPetRepo(), testLogger)
 	err := h.ValidatePetName(c)
 
 	// Assert
@@ -169,8 +170,8 @@ func TestPetNameHandler_ValidatePetName_InvalidOwnerID(t *testing.T) {
 
 	// Test
 	v := validator.New(validator.WithRequiredStructEnabled())
-	vw := validatorwrapper.NewValidatorWrapper(v)
-	h := NewPetNameHandler(vw)
+	vw := validatorwrapper.NewValidatorWrapper(v, validatorwrapper.NewNopSink(), testLogger)
+	h := NewPetNameHandler(vw, store.NewMemoryPetRepo(), testLogger)
 	err := h.ValidatePetName(c)
 
 	// Assert
@@ -197,8 +198,8 @@ func TestPetNameHandler_ValidatePetName_MissingOwnerID(t *testing.T) {
 
 	// Test
 	v := validator.New(validator.WithRequiredStructEnabled())
-	vw := validatorwrapper.NewValidatorWrapper(v)
-	h := NewPetNameHandler(vw)
+	vw := validatorwrapper.NewValidatorWrapper(v, validatorwrapper.NewNopSink(), testLogger)
+	h := NewPetNameHandler(vw, store.NewMemoryPetRepo(), testLogger)
 	err := h.ValidatePetName(c)
 
 	// Assert
@@ -226,7 +227,7 @@ func TestPetNameHandler_ValidatePetName_InternalError(t *testing.T) {
 
 	// Test - Mock validator that returns a non-validation error
 	mockV := &mockValidator{err: errors.New("unexpected internal error")}
-	h := NewPetNameHandler(mockV)
+	h := NewPetNameHandler(mockV, store.NewMemoryPetRepo(), testLogger)
 	err := h.ValidatePetName(c)
 
 	// Assert
@@ -241,8 +242,48 @@ func TestPetNameHandler_ValidatePetName_InternalError(t *testing.T) {
 
 func TestNewPetNameHandler(t *testing.T) {
 	v := validator.New(validator.WithRequiredStructEnabled())
-	vw := validatorwrapper.NewValidatorWrapper(v)
-	h := NewPetNameHandler(vw)
+	vw := validatorwrapper.NewValidatorWrapper(v, validatorwrapper.NewNopSink(), testLogger)
+	h := NewPetNameHandler(vw, store.NewMemoryPetRepo(), testLogger)
 	assert.NotNil(t, h)
-	assert.NotNil(t, h.v)
+	assert.NotNil(t, h.handle)
+}
+
+func TestPetNameHandler_ListPets(t *testing.T) {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	vw := validatorwrapper.NewValidatorWrapper(v, validatorwrapper.NewNopSink(), testLogger)
+	h := NewPetNameHandler(vw, store.NewMemoryPetRepo(), testLogger)
+	ownerID := "550e8400-e29b-41d4-a716-446655440000"
+
+	// Setup - add two pets, one of them twice
+	for _, name := range []string{"Fluffy", "Buddy", "Fluffy"} {
+		e := echo.New()
+		reqBody, _ := json.Marshal(PetNameRequest{PetName: name, OwnerID: ownerID})
+		httpReq := httptest.NewRequest(http.MethodPost, "/pet-name", bytes.NewReader(reqBody))
+		httpReq.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		assert.NoError(t, h.ValidatePetName(e.NewContext(httpReq, rec)))
+		assert.Equal(t, http.StatusOK, rec.Code)
+	}
+
+	// Test
+	e := echo.New()
+	httpReq := httptest.NewRequest(http.MethodGet, "/pets/"+ownerID, nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httpReq, rec)
+	c.SetPath("/pets/:ownerId")
+	c.SetParamNames("ownerId")
+	c.SetParamValues(ownerID)
+	err := h.ListPets(c)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var resp struct {
+		IsOK bool           `json:"isOK"`
+		Data ListPetsResult `json:"data"`
+	}
+	json.Unmarshal(rec.Body.Bytes(), &resp)
+	assert.True(t, resp.IsOK)
+	assert.Equal(t, ListPetsResult{OwnerID: ownerID, PetNames: []string{"Fluffy", "Buddy"}}, resp.Data)
 }
@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+const tooManyRequests = "too many requests"
+
+// Rate limit keys
+const (
+	RateLimitByIP     = "ip"
+	RateLimitByUserID = "userId"
+)
+
+// maxRateLimitBody bounds the part of the body read to find the userId.
+const maxRateLimitBody = 64 << 10
+
+// RateLimitStore keeps the token buckets. Implement it on a shared store
+// such as Redis to enforce the limits across instances.
+type RateLimitStore interface {
+	// Take removes a token from the bucket of key, which holds up to burst
+	// tokens and gains one each every. When the bucket is empty it returns
+	// false and the time until the next token.
+	Take(ctx context.Context, key string, burst int, every time.Duration) (bool, time.Duration, error)
+}
+
+// RateLimitRule is a token bucket per client of a route.
+type RateLimitRule struct {
+	Burst int
+	Every time.Duration
+	// Key identifies the client, RateLimitByIP or RateLimitByUserID. The
+	// userId comes from the path or the JSON body, the client IP is used
+	// when the request has none.
+	Key string
+}
+
+// RateLimitRules maps a route path, such as /api/v1/guess-cat, to its rule.
+// Routes not listed use Default.
+type RateLimitRules struct {
+	Default RateLimitRule
+	Routes  map[string]RateLimitRule
+}
+
+// RateLimit answers 429 with Retry-After once a client used up the tokens
+// of the route. The request goes through when the store fails, so an
+// outage of a shared store does not take the API down.
+func RateLimit(store RateLimitStore, rules RateLimitRules, logger *slog.Logger) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			rule, ok := rules.Routes[c.Path()]
+			if !ok {
+				rule = rules.Default
+			}
+
+			ctx := c.Request().Context()
+			key := c.Path() + "|" + rateLimitKey(c, rule.Key)
+			allowed, retryAfter, err := store.Take(ctx, key, rule.Burst, rule.Every)
+			if err != nil {
+				logger.ErrorContext(ctx, "rate limit store failed", "route", c.Path(), "error", err)
+				return next(c)
+			}
+			if allowed {
+				return next(c)
+			}
+
+			seconds := int(math.Ceil(retryAfter.Seconds()))
+			c.Response().Header().Set(echo.HeaderRetryAfter, strconv.Itoa(max(seconds, 1)))
+			return respond(c, http.StatusTooManyRequests, Response{
+				IsOK: false,
+				Code: CodeRateLimited,
+				Msg:  tooManyRequests,
+			})
+		}
+	}
+}
+
+// rateLimitKey identifies the client of the request.
+func rateLimitKey(c echo.Context, by string) string {
+	if by == RateLimitByUserID {
+		if userID := requestUserID(c); userID != "" {
+			return "userId:" + userID
+		}
+	}
+	return "ip:" + c.RealIP()
+}
+
+// requestUserID reads the userId from the path or the JSON body, leaving
+// the body intact for the handler.
+func requestUserID(c echo.Context) string {
+	if userID := c.Param("userId"); userID != "" {
+		return userID
+	}
+
+	req := c.Request()
+	if req.Body == nil || req.Body == http.NoBody {
+		return ""
+	}
+	prefix, err := io.ReadAll(io.LimitReader(req.Body, maxRateLimitBody+1))
+	req.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(prefix), req.Body), req.Body}
+	if err != nil || len(prefix) > maxRateLimitBody {
+		return ""
+	}
+
+	var body struct {
+		UserID string `json:"userId"`
+	}
+	if json.Unmarshal(prefix, &body) != nil {
+		return ""
+	}
+	return body.UserID
+}
@@ -0,0 +1,185 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingStore allows the first burst takes of every key
+type countingStore struct {
+	taken map[string]int
+	err   error
+}
+
+func (s *countingStore) Take(ctx context.Context, key string, burst int, every time.Duration) (bool, time.Duration, error) {
+	if s.err != nil {
+		return false, 0, s.err
+	}
+	if s.taken == nil {
+		s.taken = map[string]int{}
+	}
+	s.taken[key]++
+	if s.taken[key] > burst {
+		return false, 1500 * time.Millisecond, nil
+	}
+	return true, 0, nil
+}
+
+var testRateLimitRules = RateLimitRules{
+	Default: RateLimitRule{Burst: 2, Every: time.Second, Key: RateLimitByIP},
+	Routes: map[string]RateLimitRule{
+		"/guess": {Burst: 1, Every: time.Second, Key: RateLimitByUserID},
+	},
+}
+
+func newRateLimitTestServer(store RateLimitStore) *echo.Echo {
+	e := echo.New()
+	e.HTTPErrorHandler = NewHTTPErrorHandler(testLogger)
+	e.IPExtractor = echo.ExtractIPDirect()
+	limit := RateLimit(store, testRateLimitRules, testLogger)
+	e.GET("/echo", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, newOkResponse())
+	}, limit)
+	e.POST("/guess", func(c echo.Context) error {
+		// the handler still reads the whole body
+		b, err := io.ReadAll(c.Request().Body)
+		if err != nil {
+			return err
+		}
+		return c.JSON(http.StatusOK, newDataResponse(string(b)))
+	}, limit)
+	return e
+}
+
+func serveRateLimited(e *echo.Echo, method, path, ip, body string) *httptest.ResponseRecorder {
+	httpReq := httptest.NewRequest(method, path, strings.NewReader(body))
+	httpReq.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	httpReq.RemoteAddr = ip + ":40000"
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httpReq)
+	return rec
+}
+
+func TestRateLimit_ByIP(t *testing.T) {
+	// Setup
+	e := newRateLimitTestServer(&countingStore{})
+
+	// Test
+	var codes []int
+	for i := 0; i < 3; i++ {
+		codes = append(codes, serveRateLimited(e, http.MethodGet, "/echo", "192.0.2.1", "").Code)
+	}
+	rec := serveRateLimited(e, http.MethodGet, "/echo", "192.0.2.1", "")
+	other := serveRateLimited(e, http.MethodGet, "/echo", "192.0.2.2", "")
+
+	// Assert
+	assert.Equal(t, []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests}, codes)
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "2", rec.Header().Get(echo.HeaderRetryAfter))
+	var resp Response
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, Response{IsOK: false, Code: CodeRateLimited, Msg: tooManyRequests}, resp)
+	assert.Equal(t, http.StatusOK, other.Code)
+}
+
+func TestRateLimit_SpoofedForwardedForStillLimited(t *testing.T) {
+	// Setup
+	e := newRateLimitTestServer(&countingStore{})
+
+	// Test
+	var codes []int
+	for i := 0; i < 3; i++ {
+		httpReq := httptest.NewRequest(http.MethodGet, "/echo", nil)
+		httpReq.RemoteAddr = "192.0.2.1:40000"
+		httpReq.Header.Set(echo.HeaderXForwardedFor, fmt.Sprintf("198.51.100.%d", i))
+		httpReq.Header.Set(echo.HeaderXRealIP, fmt.Sprintf("198.51.100.%d", i))
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httpReq)
+		codes = append(codes, rec.Code)
+	}
+
+	// Assert
+	assert.Equal(t, []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests}, codes)
+}
+
+func TestRateLimit_ByUserID(t *testing.T) {
+	// Setup
+	store := &countingStore{}
+	e := newRateLimitTestServer(store)
+	body := `{"userId": "af519cc4-56c4-4da9-bb2d-37fd215b17fe", "guessName": "Fluffy"}`
+
+	// Test
+	first := serveRateLimited(e, http.MethodPost, "/guess", "192.0.2.1", body)
+	sameUser := serveRateLimited(e, http.MethodPost, "/guess", "192.0.2.2", body)
+	otherUser := serveRateLimited(e, http.MethodPost, "/guess", "192.0.2.1", `{"userId": "0b7d3a0e-3c5a-4d8e-9a57-2f0e6f1f6c11"}`)
+	noUser := serveRateLimited(e, http.MethodPost, "/guess", "192.0.2.1", `{"guessName": "Fluffy"}`)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, first.Code)
+	var resp Response
+	require.NoError(t, json.Unmarshal(first.Body.Bytes(), &resp))
+	assert.Equal(t, body, resp.Data)
+	assert.Equal(t, http.StatusTooManyRequests, sameUser.Code)
+	assert.Equal(t, http.StatusOK, otherUser.Code)
+	assert.Equal(t, http.StatusOK, noUser.Code)
+	assert.Contains(t, store.taken, "/guess|ip:192.0.2.1")
+}
+
+func TestRateLimit_LargeBodyKeptIntact(t *testing.T) {
+	// Setup
+	e := newRateLimitTestServer(&countingStore{})
+	body := `{"userId": "a", "pad": "` + strings.Repeat("x", maxRateLimitBody) + `"}`
+
+	// Test
+	rec := serveRateLimited(e, http.MethodPost, "/guess", "192.0.2.1", body)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var resp Response
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, body, resp.Data)
+}
+
+func TestRateLimit_StoreErrorLetsRequestsThrough(t *testing.T) {
+	// Setup
+	e := newRateLimitTestServer(&countingStore{err: errors.New("connection refused")})
+
+	// Test
+	rec := serveRateLimited(e, http.MethodGet, "/echo", "192.0.2.1", "")
+
+	// Assert
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestRateLimit_Problem(t *testing.T) {
+	// Setup
+	e := newRateLimitTestServer(&countingStore{})
+	serveRateLimited(e, http.MethodPost, "/guess", "192.0.2.1", "")
+	httpReq := httptest.NewRequest(http.MethodPost, "/guess", bytes.NewReader(nil))
+	httpReq.RemoteAddr = "192.0.2.1:40000"
+	httpReq.Header.Set(echo.HeaderAccept, MIMEApplicationProblemJSON)
+	rec := httptest.NewRecorder()
+
+	// Test
+	e.ServeHTTP(rec, httpReq)
+
+	// Assert
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, MIMEApplicationProblemJSON, rec.Header().Get(echo.HeaderContentType))
+	var problem Problem
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+	assert.Equal(t, CodeRateLimited, problem.Code)
+}
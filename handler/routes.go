@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Route documents an endpoint for the OpenAPI spec generated by
+// cmd/openapi. main registers the same routes with RegisterRoutes.
+type Route struct {
+	Method  string
+	Path    string
+	Summary string
+	// Request is the struct bound from the request, nil when there is none.
+	Request any
+	// Data is the type of Response.Data on success, nil when there is none.
+	Data any
+	// Errors lists the non-200 statuses the endpoint answers with.
+	Errors []int
+	// Stream marks endpoints exchanging JSON Lines. Data then describes a
+	// single line of the response.
+	Stream bool
+}
+
+// Routes lists the documented endpoints. Run go generate to refresh
+// openapi.yaml after changing it.
+var Routes = []Route{
+	{
+		Method:  http.MethodPost,
+		Path:    "/api/v1/favorite",
+		Summary: "Save the favorite number of a user",
+		Request: FavoriteNumRequest{},
+		Errors:  []int{http.StatusBadRequest, http.StatusTooManyRequests, http.StatusInternalServerError},
+	},
+	{
+		Method:  http.MethodGet,
+		Path:    "/api/v1/favorite/:userId",
+		Summary: "Get the favorite number of a user",
+		Request: GetFavoriteNumRequest{},
+		Data:    FavoriteNumResult{},
+		Errors:  []int{http.StatusBadRequest, http.StatusNotFound, http.StatusTooManyRequests, http.StatusInternalServerError},
+	},
+	{
+		Method:  http.MethodPost,
+		Path:    "/api/v1/pet-name",
+		Summary: "Add a pet name to an owner",
+		Request: PetNameRequest{},
+		Errors:  []int{http.StatusBadRequest, http.StatusTooManyRequests, http.StatusInternalServerError},
+	},
+	{
+		Method:  http.MethodGet,
+		Path:    "/api/v1/pets/:ownerId",
+		Summary: "List the pet names of an owner",
+		Request: ListPetsRequest{},
+		Data:    ListPetsResult{},
+		Errors:  []int{http.StatusBadRequest, http.StatusTooManyRequests, http.StatusInternalServerError},
+	},
+	{
+		Method:  http.MethodPost,
+		Path:    "/api/v1/thai-cid",
+		Summary: "Validate a Thai citizen ID",
+		Request: ThaiCIDRequest{},
+		Errors:  []int{http.StatusBadRequest, http.StatusTooManyRequests, http.StatusInternalServerError},
+	},
+	{
+		Method:  http.MethodPost,
+		Path:    "/api/v1/guess-cat",
+		Summary: "Guess the name of the cat",
+		Request: GuessCatNameRequest{},
+		Data:    GuessCatNameResult{},
+		Errors:  []int{http.StatusBadRequest, http.StatusTooManyRequests, http.StatusInternalServerError},
+	},
+	{
+		Method:  http.MethodPost,
+		Path:    "/api/v1/batch/:kind",
+		Summary: "Validate a JSON Lines body, one request per line",
+		Request: BatchRequest{},
+		Data:    BatchLineResult{},
+		Errors:  []int{http.StatusBadRequest, http.StatusTooManyRequests, http.StatusInternalServerError},
+		Stream:  true,
+	},
+	{
+		Method:  http.MethodGet,
+		Path:    "/admin/validation-errors",
+		Summary: "Query the recorded validation failures, or the top failing fields",
+		Request: ValidationErrorsRequest{},
+		Data:    ValidationErrorsResult{},
+		Errors:  []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusUnprocessableEntity, http.StatusTooManyRequests, http.StatusInternalServerError},
+	},
+	{
+		Method:  http.MethodGet,
+		Path:    "/healthz",
+		Summary: "Liveness probe",
+	},
+	{
+		Method:  http.MethodGet,
+		Path:    "/readyz",
+		Summary: "Readiness probe, pings the injected dependencies",
+		Data:    map[string]string{},
+		Errors:  []int{http.StatusServiceUnavailable},
+	},
+	{
+		Method:  http.MethodGet,
+		Path:    "/version",
+		Summary: "Build information",
+		Data:    BuildInfo{},
+	},
+}
+
+// RegisterRoutes adds every route to e, served by the handler keyed by its
+// method and path in handlers, e.g. "GET /readyz", behind the middlewares mw
+// returns for it. A nil handler leaves its route out, e.g. when disabled by
+// config. A route without a handler, or a handler without a route, is an
+// error, so the spec cannot drift from what is served.
+func RegisterRoutes(e *echo.Echo, routes []Route, handlers map[string]echo.HandlerFunc, mw func(Route) []echo.MiddlewareFunc) error {
+	unused := make(map[string]bool, len(handlers))
+	for key := range handlers {
+		unused[key] = true
+	}
+	for _, r := range routes {
+		key := r.Method + " " + r.Path
+		h, ok := handlers[key]
+		if !ok {
+			return fmt.Errorf("route %s has no handler", key)
+		}
+		delete(unused, key)
+		if h != nil {
+			e.Add(r.Method, r.Path, h, mw(r)...)
+		}
+	}
+	if len(unused) > 0 {
+		keys := make([]string, 0, len(unused))
+		for key := range unused {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		return fmt.Errorf("handlers without a documented route: %v", keys)
+	}
+	return nil
+}
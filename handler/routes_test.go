@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func noContent(c echo.Context) error {
+	return c.NoContent(http.StatusNoContent)
+}
+
+func noMiddleware(Route) []echo.MiddlewareFunc {
+	return nil
+}
+
+// routeHandlers serves every route of Routes with noContent.
+func routeHandlers() map[string]echo.HandlerFunc {
+	handlers := map[string]echo.HandlerFunc{}
+	for _, r := range Routes {
+		handlers[r.Method+" "+r.Path] = noContent
+	}
+	return handlers
+}
+
+func registeredRoutes(e *echo.Echo) []string {
+	var keys []string
+	for _, r := range e.Routes() {
+		keys = append(keys, r.Method+" "+r.Path)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func TestRegisterRoutes(t *testing.T) {
+	// Setup
+	e := echo.New()
+	handlers := routeHandlers()
+	want := make([]string, 0, len(handlers))
+	for key := range handlers {
+		want = append(want, key)
+	}
+	sort.Strings(want)
+
+	// Test
+	err := RegisterRoutes(e, Routes, handlers, noMiddleware)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, want, registeredRoutes(e))
+}
+
+func TestRegisterRoutes_NilHandlerLeavesRouteOut(t *testing.T) {
+	// Setup
+	e := echo.New()
+	handlers := routeHandlers()
+	handlers["GET /admin/validation-errors"] = nil
+
+	// Test
+	err := RegisterRoutes(e, Routes, handlers, noMiddleware)
+
+	// Assert
+	require.NoError(t, err)
+	assert.NotContains(t, registeredRoutes(e), "GET /admin/validation-errors")
+	assert.Len(t, registeredRoutes(e), len(Routes)-1)
+}
+
+func TestRegisterRoutes_Drift(t *testing.T) {
+	t.Run("route without handler", func(t *testing.T) {
+		handlers := routeHandlers()
+		delete(handlers, "GET /readyz")
+
+		err := RegisterRoutes(echo.New(), Routes, handlers, noMiddleware)
+
+		assert.ErrorContains(t, err, "GET /readyz")
+	})
+	t.Run("handler without route", func(t *testing.T) {
+		handlers := routeHandlers()
+		handlers["DELETE /api/v1/favorite/:userId"] = noContent
+
+		err := RegisterRoutes(echo.New(), Routes, handlers, noMiddleware)
+
+		assert.ErrorContains(t, err, "DELETE /api/v1/favorite/:userId")
+	})
+}
+
+func TestRegisterRoutes_Middlewares(t *testing.T) {
+	// Setup
+	e := echo.New()
+	var seen []string
+	mw := func(r Route) []echo.MiddlewareFunc {
+		return []echo.MiddlewareFunc{func(next echo.HandlerFunc) echo.HandlerFunc {
+			return func(c echo.Context) error {
+				seen = append(seen, r.Path)
+				return next(c)
+			}
+		}}
+	}
+	require.NoError(t, RegisterRoutes(e, Routes, routeHandlers(), mw))
+
+	// Test
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	// Assert
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, []string{"/healthz"}, seen)
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -18,7 +19,7 @@ func TestThaiCIDHandler_ValidateThaiCID_Success(t *testing.T) {
 	// Setup
 	e := echo.New()
 	req := ThaiCIDRequest{
-		CitizenID: "1234567890123",
+		CitizenID: "1234567890121",
 		FullName:  "John Doe",
 	}
 	reqBody, _ := json.Marshal(req)
@@ -29,8 +30,8 @@ func TestThaiCIDHandler_ValidateThaiCID_Success(t *testing.T) {
 
 	// Test
 	v := validator.New(validator.WithRequiredStructEnabled())
-	vw := validatorwrapper.NewValidatorWrapper(v)
-	h := NewThaiCIDHandler(vw)
+	vw := validatorwrapper.NewValidatorWrapper(v, validatorwrapper.NewNopSink(), testLogger)
+	h := NewThaiCIDHandler(vw, testLogger)
 	err := h.ValidateThaiCID(c)
 
 	// Assert
@@ -54,8 +55,8 @@ func TestThaiCIDHandler_ValidateThaiCID_InvalidJSON(t *testing.T) {
 
 	// Test
 	v := validator.New(validator.WithRequiredStructEnabled())
-	vw := validatorwrapper.NewValidatorWrapper(v)
-	h := NewThaiCIDHandler(vw)
+	vw := validatorwrapper.NewValidatorWrapper(v, validatorwrapper.NewNopSink(), testLogger)
+	h := NewThaiCIDHandler(vw, testLogger)
 	err := h.ValidateThaiCID(c)
 
 	// Assert
@@ -82,8 +83,8 @@ func TestThaiCIDHandler_ValidateThaiCID_MissingCitizenID(t *testing.T) {
 
 	// Test
 	v := validator.New(validator.WithRequiredStructEnabled())
-	vw := validatorwrapper.NewValidatorWrapper(v)
-	h := NewThaiCIDHandler(vw)
+	vw := validatorwrapper.NewValidatorWrapper(v, validatorwrapper.NewNopSink(), testLogger)
+	h := NewThaiCIDHandler(vw, testLogger)
 	err := h.ValidateThaiCID(c)
 
 	// Assert
@@ -111,8 +112,8 @@ func TestThaiCIDHandler_ValidateThaiCID_CitizenIDTooShort(t *testing.T) {
 
 	// Test
 	v := validator.New(validator.WithRequiredStructEnabled())
-	vw := validatorwrapper.NewValidatorWrapper(v)
-	h := NewThaiCIDHandler(vw)
+	vw := validatorwrapper.NewValidatorWrapper(v, validatorwrapper.NewNopSink(), testLogger)
+	h := NewThaiCIDHandler(vw, testLogger)
 	err := h.ValidateThaiCID(c)
 
 	// Assert
@@ -140,8 +141,8 @@ func TestThaiCIDHandler_ValidateThaiCID_CitizenIDTooLong(t *testing.T) {
 
 	// Test
 	v := validator.New(validator.WithRequiredStructEnabled())
-	vw := validatorwrapper.NewValidatorWrapper(v)
-	h := NewThaiCIDHandler(vw)
+	vw := validatorwrapper.NewValidatorWrapper(v, validatorwrapper.NewNopSink(), testLogger)
+	h := NewThaiCIDHandler(vw, testLogger)
 	err := h.ValidateThaiCID(c)
 
 	// Assert
@@ -169,8 +170,8 @@ func TestThaiCIDHandler_ValidateThaiCID_CitizenIDNotNumeric(t *testing.T) {
 
 	// Test
 	v := validator.New(validator.WithRequiredStructEnabled())
-	vw := validatorwrapper.NewValidatorWrapper(v)
-	h := NewThaiCIDHandler(vw)
+	vw := validatorwrapper.NewValidatorWrapper(v, validatorwrapper.NewNopSink(), testLogger)
+	h := NewThaiCIDHandler(vw, testLogger)
 	err := h.ValidateThaiCID(c)
 
 	// Assert
@@ -187,7 +188,7 @@ func TestThaiCIDHandler_ValidateThaiCID_MissingFullName(t *testing.T) {
 	// Setup
 	e := echo.New()
 	req := ThaiCIDRequest{
-		CitizenID: "1234567890123",
+		CitizenID: "1234567890121",
 	}
 	reqBody, _ := json.Marshal(req)
 	httpReq := httptest.NewRequest(http.MethodPost, "/thai-cid", bytes.NewReader(reqBody))
@@ -197,8 +198,8 @@ func TestThaiCIDHandler_ValidateThaiCID_MissingFullName(t *testing.T) {
 
 	// Test
 	v := validator.New(validator.WithRequiredStructEnabled())
-	vw := validatorwrapper.NewValidatorWrapper(v)
-	h := NewThaiCIDHandler(vw)
+	vw := validatorwrapper.NewValidatorWrapper(v, validatorwrapper.NewNopSink(), testLogger)
+	h := NewThaiCIDHandler(vw, testLogger)
 	err := h.ValidateThaiCID(c)
 
 	// Assert
@@ -215,7 +216,7 @@ func TestThaiCIDHandler_ValidateThaiCID_FullNameTooShort(t *testing.T) {
 	// Setup
 	e := echo.New()
 	req := ThaiCIDRequest{
-		CitizenID: "1234567890123",
+		CitizenID: "1234567890121",
 		FullName:  "AB",
 	}
 	reqBody, _ := json.Marshal(req)
@@ -226,8 +227,8 @@ func TestThaiCIDHandler_ValidateThaiCID_FullNameTooShort(t *testing.T) {
 
 	// Test
 	v := validator.New(validator.WithRequiredStructEnabled())
-	vw := validatorwrapper.NewValidatorWrapper(v)
-	h := NewThaiCIDHandler(vw)
+	vw := validatorwrapper.NewValidatorWrapper(v, validatorwrapper.NewNopSink(), testLogger)
+	h := NewThaiCIDHandler(vw, testLogger)
 	err := h.ValidateThaiCID(c)
 
 	// Assert
@@ -240,11 +241,64 @@ func TestThaiCIDHandler_ValidateThaiCID_FullNameTooShort(t *testing.T) {
 	assert.Equal(t, badRequestNotValid, resp.Msg)
 }
 
+func TestThaiCIDHandler_ValidateThaiCID_Checksum(t *testing.T) {
+	tests := []struct {
+		name       string
+		citizenID  string
+		wantStatus int
+	}{
+		{name: "valid", citizenID: "1234567890121", wantStatus: http.StatusOK},
+		{name: "valid starting with 3", citizenID: "3100500123458", wantStatus: http.StatusOK},
+		{name: "valid check digit zero", citizenID: "1101700203450", wantStatus: http.StatusOK},
+		{name: "wrong check digit", citizenID: "1234567890123", wantStatus: http.StatusBadRequest},
+		{name: "leading zero", citizenID: "0123456789016", wantStatus: http.StatusBadRequest},
+		{name: "leading nine", citizenID: "9123456789010", wantStatus: http.StatusBadRequest},
+	}
+
+	v := validator.New(validator.WithRequiredStructEnabled())
+	vw := validatorwrapper.NewValidatorWrapper(v, validatorwrapper.NewNopSink(), testLogger)
+	h := NewThaiCIDHandler(vw, testLogger)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			e := echo.New()
+			req := ThaiCIDRequest{
+				CitizenID: tt.citizenID,
+				FullName:  "John Doe",
+			}
+			reqBody, _ := json.Marshal(req)
+			httpReq := httptest.NewRequest(http.MethodPost, "/thai-cid", bytes.NewReader(reqBody))
+			httpReq.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(httpReq, rec)
+
+			// Test
+			err := h.ValidateThaiCID(c)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, rec.Code)
+
+			var resp Response
+			json.Unmarshal(rec.Body.Bytes(), &resp)
+			if tt.wantStatus == http.StatusOK {
+				assert.True(t, resp.IsOK)
+				return
+			}
+			assert.False(t, resp.IsOK)
+			assert.Equal(t, []FieldError{
+				{Field: "citizenId", Code: CodeFieldInvalid, Tag: "thai_cid", Value: tt.citizenID, Message: "citizenId must be a valid Thai citizen ID"},
+			}, resp.Errors)
+		})
+	}
+}
+
 func TestThaiCIDHandler_ValidateThaiCID_InternalError(t *testing.T) {
 	// Setup
 	e := echo.New()
 	req := ThaiCIDRequest{
-		CitizenID: "1234567890123",
+		CitizenID: "1234567890121",
 		FullName:  "John Doe",
 	}
 	reqBody, _ := json.Marshal(req)
@@ -255,7 +309,7 @@ func TestThaiCIDHandler_ValidateThaiCID_InternalError(t *testing.T) {
 
 	// Test - Mock validator that returns a non-validation error
 	mockV := &mockValidator{err: errors.New("unexpected internal error")}
-	h := NewThaiCIDHandler(mockV)
+	h := NewThaiCIDHandler(mockV, testLogger)
 	err := h.ValidateThaiCID(c)
 
 	// Assert
@@ -268,10 +322,71 @@ func TestThaiCIDHandler_ValidateThaiCID_InternalError(t *testing.T) {
 	assert.Equal(t, "internal server error", resp.Msg)
 }
 
+func TestThaiCIDHandler_ValidateThaiCID_InternalErrorRedactsLogs(t *testing.T) {
+	// Setup
+	e := echo.New()
+	req := ThaiCIDRequest{
+		CitizenID: "1234567890121",
+		FullName:  "Somchai Jaidee",
+	}
+	reqBody, _ := json.Marshal(req)
+	httpReq := httptest.NewRequest(http.MethodPost, "/thai-cid", bytes.NewReader(reqBody))
+	httpReq.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httpReq, rec)
+
+	// Test - log the internal error into a buffer
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	mockV := &mockValidator{err: errors.New("unexpected internal error")}
+	h := NewThaiCIDHandler(mockV, logger)
+	err := h.ValidateThaiCID(c)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.NotContains(t, logs.String(), req.CitizenID)
+	assert.NotContains(t, logs.String(), "Somchai")
+	assert.Contains(t, logs.String(), "*********0121")
+}
+
+func TestThaiCIDHandler_ValidateThaiCID_ValidationErrorRedactsSink(t *testing.T) {
+	// Setup - right length and digits but wrong checksum
+	e := echo.New()
+	req := ThaiCIDRequest{
+		CitizenID: "1234567890123",
+		FullName:  "Jo",
+	}
+	reqBody, _ := json.Marshal(req)
+	httpReq := httptest.NewRequest(http.MethodPost, "/thai-cid", bytes.NewReader(reqBody))
+	httpReq.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httpReq, rec)
+
+	// Test - record validation errors as JSON lines into a buffer
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	sink := &bufferSink{}
+	v := validator.New(validator.WithRequiredStructEnabled())
+	vw := validatorwrapper.NewValidatorWrapper(v, sink, logger)
+	h := NewThaiCIDHandler(vw, logger)
+	err := h.ValidateThaiCID(c)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	recorded, _ := json.Marshal(sink.records)
+	assert.Len(t, sink.records, 2)
+	assert.NotContains(t, string(recorded), req.CitizenID)
+	assert.NotContains(t, string(recorded), `"Jo"`)
+	assert.Contains(t, string(recorded), "*********0123")
+	assert.NotContains(t, logs.String(), req.CitizenID)
+}
+
 func TestNewThaiCIDHandler(t *testing.T) {
 	v := validator.New(validator.WithRequiredStructEnabled())
-	vw := validatorwrapper.NewValidatorWrapper(v)
-	h := NewThaiCIDHandler(vw)
+	vw := validatorwrapper.NewValidatorWrapper(v, validatorwrapper.NewNopSink(), testLogger)
+	h := NewThaiCIDHandler(vw, testLogger)
 	assert.NotNil(t, h)
-	assert.NotNil(t, h.v)
+	assert.NotNil(t, h.handle)
 }
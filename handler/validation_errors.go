@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/BoomNooB/medium-go-di/validatorwrapper"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	defaultValidationErrorsLimit = 50
+	tooManyRecordsToScan         = "too many recorded errors in the time range, narrow from and to"
+)
+
+type ValidationErrorsRequest struct {
+	From      time.Time `query:"from"`
+	To        time.Time `query:"to" validate:"omitempty,gtfield=From"`
+	Namespace string    `query:"namespace" validate:"omitempty,max=200"`
+	Tag       string    `query:"tag" validate:"omitempty,max=50"`
+	Offset    int       `query:"offset" validate:"gte=0"`
+	Limit     int       `query:"limit" validate:"omitempty,gte=1,lte=500"`
+	// Top switches to the aggregate mode: the Top most failing fields
+	// instead of the records.
+	Top int `query:"top" validate:"omitempty,gte=1,lte=100"`
+}
+
+type ValidationErrorsResult struct {
+	// Total is the number of records matching the filters.
+	Total   int                            `json:"total"`
+	Records []validatorwrapper.ErrorRecord `json:"records,omitempty"`
+	Top     []validatorwrapper.FieldCount  `json:"top,omitempty"`
+}
+
+// ValidationErrorQuerier reads back the recorded validation failures.
+type ValidationErrorQuerier interface {
+	List(ctx context.Context, f validatorwrapper.ErrorFilter, offset, limit int) ([]validatorwrapper.ErrorRecord, int, error)
+	Top(ctx context.Context, f validatorwrapper.ErrorFilter, n int) ([]validatorwrapper.FieldCount, int, error)
+}
+
+type ValidationErrorsHandler struct {
+	querier ValidationErrorQuerier
+	handle  echo.HandlerFunc
+}
+
+// NewValidationErrorsHandler serves the recorded validation failures to
+// admins, so product can see which inputs users get wrong most. Give it a
+// validator that records nothing, admin typos would mix with that data.
+func NewValidationErrorsHandler(validator Valiator, querier ValidationErrorQuerier, logger *slog.Logger) *ValidationErrorsHandler {
+	vh := &ValidationErrorsHandler{
+		querier: querier,
+	}
+	vh.handle = ValidateEndpoint(validator, logger, vh.query)
+	return vh
+}
+
+func (vh *ValidationErrorsHandler) ListValidationErrors(c echo.Context) error {
+	return vh.handle(c)
+}
+
+func (vh *ValidationErrorsHandler) query(ctx context.Context, req *ValidationErrorsRequest) (Response, error) {
+	filter := validatorwrapper.ErrorFilter{
+		From:      req.From,
+		To:        req.To,
+		Namespace: req.Namespace,
+		Tag:       req.Tag,
+	}
+
+	if req.Top > 0 {
+		top, total, err := vh.querier.Top(ctx, filter, req.Top)
+		if err != nil {
+			return Response{}, scanError(err)
+		}
+		return newDataResponse(ValidationErrorsResult{Total: total, Top: top}), nil
+	}
+
+	limit := req.Limit
+	if limit == 0 {
+		limit = defaultValidationErrorsLimit
+	}
+	records, total, err := vh.querier.List(ctx, filter, req.Offset, limit)
+	if err != nil {
+		return Response{}, scanError(err)
+	}
+	return newDataResponse(ValidationErrorsResult{Total: total, Records: records}), nil
+}
+
+// scanError answers 422 when the time range of a query holds more records
+// than it may read.
+func scanError(err error) error {
+	if errors.Is(err, validatorwrapper.ErrScanLimit) {
+		return newStatusError(http.StatusUnprocessableEntity, newBadRequestResponse(CodeTooManyRecords, tooManyRecordsToScan))
+	}
+	return err
+}
@@ -0,0 +1,170 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/BoomNooB/medium-go-di/validatorwrapper"
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordScanner serves validation error records from memory
+type recordScanner []validatorwrapper.ErrorRecord
+
+func (s recordScanner) Scan(ctx context.Context, fn func(validatorwrapper.ErrorRecord) error) error {
+	for _, r := range s {
+		if err := fn(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var recordedErrors = recordScanner{
+	{Timestamp: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC), StructNamespace: "FavoriteNumRequest.UserID", Tag: "uuid_rfc4122"},
+	{Timestamp: time.Date(2025, 1, 2, 3, 4, 6, 0, time.UTC), StructNamespace: "PetNameRequest.PetName", Tag: "min"},
+	{Timestamp: time.Date(2025, 1, 3, 3, 4, 5, 0, time.UTC), StructNamespace: "FavoriteNumRequest.UserID", Tag: "required"},
+}
+
+func serveValidationErrors(t *testing.T, querier ValidationErrorQuerier, query string) (*httptest.ResponseRecorder, Response) {
+	t.Helper()
+	e := echo.New()
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/admin/validation-errors?"+query, nil), rec)
+
+	v := validator.New(validator.WithRequiredStructEnabled())
+	vw := validatorwrapper.NewValidatorWrapper(v, validatorwrapper.NewNopSink(), testLogger)
+	h := NewValidationErrorsHandler(vw, querier, testLogger)
+	require.NoError(t, h.ListValidationErrors(c))
+
+	var resp Response
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	return rec, resp
+}
+
+func TestValidationErrorsHandler_List(t *testing.T) {
+	// Setup
+	querier := validatorwrapper.NewErrorQuerier(recordedErrors, 0)
+
+	// Test
+	rec, resp := serveValidationErrors(t, querier, "namespace=FavoriteNumRequest&from=2025-01-02T00:00:00Z&to=2025-01-03T00:00:00Z")
+
+	// Assert
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, resp.IsOK)
+	assert.JSONEq(t, `{
+		"total": 1,
+		"records": [{"timestamp": "2025-01-02T03:04:05Z", "structAndFieldName": "FavoriteNumRequest.UserID", "errorTag": "uuid_rfc4122"}]
+	}`, string(mustMarshal(t, resp.Data)))
+}
+
+func TestValidationErrorsHandler_Pagination(t *testing.T) {
+	// Setup
+	querier := validatorwrapper.NewErrorQuerier(recordedErrors, 0)
+
+	// Test
+	rec, resp := serveValidationErrors(t, querier, "offset=1&limit=1")
+
+	// Assert
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{
+		"total": 3,
+		"records": [{"timestamp": "2025-01-02T03:04:06Z", "structAndFieldName": "PetNameRequest.PetName", "errorTag": "min"}]
+	}`, string(mustMarshal(t, resp.Data)))
+}
+
+func TestValidationErrorsHandler_Top(t *testing.T) {
+	// Setup
+	querier := validatorwrapper.NewErrorQuerier(recordedErrors, 0)
+
+	// Test
+	rec, resp := serveValidationErrors(t, querier, "top=1")
+
+	// Assert
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{
+		"total": 3,
+		"top": [{"structAndFieldName": "FavoriteNumRequest.UserID", "count": 2, "tags": {"uuid_rfc4122": 1, "required": 1}}]
+	}`, string(mustMarshal(t, resp.Data)))
+}
+
+func TestValidationErrorsHandler_InvalidQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		code  ErrorCode
+		msg   string
+	}{
+		{name: "to before from", query: "from=2025-01-03T00:00:00Z&to=2025-01-02T00:00:00Z", code: CodeValidationFailed, msg: badRequestNotValid},
+		{name: "limit too big", query: "limit=1000", code: CodeValidationFailed, msg: badRequestNotValid},
+		{name: "negative offset", query: "offset=-1", code: CodeValidationFailed, msg: badRequestNotValid},
+		{name: "bad time", query: "from=yesterday", code: CodeBadRequest, msg: badRequestParams},
+		{name: "bad limit", query: "limit=abc", code: CodeBadRequest, msg: badRequestParams},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			querier := validatorwrapper.NewErrorQuerier(recordedErrors, 0)
+
+			// Test
+			rec, resp := serveValidationErrors(t, querier, tt.query)
+
+			// Assert
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			assert.Equal(t, tt.code, resp.Code)
+			assert.Equal(t, tt.msg, resp.Msg)
+		})
+	}
+}
+
+func TestValidationErrorsHandler_StorageError(t *testing.T) {
+	// Setup
+	querier := validatorwrapper.NewErrorQuerier(scanFunc(func(ctx context.Context, fn func(validatorwrapper.ErrorRecord) error) error {
+		return errors.New("permission denied")
+	}), 0)
+
+	// Test
+	rec, resp := serveValidationErrors(t, querier, "")
+
+	// Assert
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Equal(t, CodeInternal, resp.Code)
+}
+
+func TestValidationErrorsHandler_ScanLimit(t *testing.T) {
+	// Setup
+	querier := validatorwrapper.NewErrorQuerier(recordedErrors, 1)
+
+	// Test
+	rec, resp := serveValidationErrors(t, querier, "")
+	topRec, topResp := serveValidationErrors(t, querier, "top=1")
+	narrowRec, _ := serveValidationErrors(t, querier, "from=2025-01-03T00:00:00Z")
+
+	// Assert
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Equal(t, Response{IsOK: false, Code: CodeTooManyRecords, Msg: tooManyRecordsToScan}, resp)
+	assert.Equal(t, http.StatusUnprocessableEntity, topRec.Code)
+	assert.Equal(t, CodeTooManyRecords, topResp.Code)
+	assert.Equal(t, http.StatusOK, narrowRec.Code)
+}
+
+type scanFunc func(ctx context.Context, fn func(validatorwrapper.ErrorRecord) error) error
+
+func (f scanFunc) Scan(ctx context.Context, fn func(validatorwrapper.ErrorRecord) error) error {
+	return f(ctx, fn)
+}
+
+func mustMarshal(t *testing.T, v any) []byte {
+	t.Helper()
+	b, err := json.Marshal(v)
+	require.NoError(t, err)
+	return b
+}
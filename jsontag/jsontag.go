@@ -0,0 +1,21 @@
+// Package jsontag reads the json tags of struct fields the way encoding/json
+// does, for the packages that describe or report request fields by name.
+package jsontag
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Name returns the name encoding/json uses for f, or "" when the field is
+// skipped with json:"-".
+func Name(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		return f.Name
+	}
+	return name
+}
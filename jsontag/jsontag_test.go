@@ -0,0 +1,21 @@
+package jsontag
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestName(t *testing.T) {
+	typ := reflect.TypeOf(struct {
+		Tagged   string `json:"tagged,omitempty"`
+		Untagged string
+		Options  string `json:",omitempty"`
+		Skipped  string `json:"-"`
+	}{})
+	assert.Equal(t, "tagged", Name(typ.Field(0)))
+	assert.Equal(t, "Untagged", Name(typ.Field(1)))
+	assert.Equal(t, "Options", Name(typ.Field(2)))
+	assert.Equal(t, "", Name(typ.Field(3)))
+}
@@ -1,39 +1,338 @@
+//go:generate go run ./cmd/openapi -o openapi.yaml
+
 package main
 
 import (
-	"log"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"runtime"
+	"runtime/debug"
+	"slices"
+	"strings"
+	"syscall"
 
+	"github.com/BoomNooB/medium-go-di/config"
 	"github.com/BoomNooB/medium-go-di/handler"
+	"github.com/BoomNooB/medium-go-di/metrics"
+	"github.com/BoomNooB/medium-go-di/openapi"
+	"github.com/BoomNooB/medium-go-di/requestid"
+	"github.com/BoomNooB/medium-go-di/store"
+	"github.com/BoomNooB/medium-go-di/tracing"
 	"github.com/BoomNooB/medium-go-di/validatorwrapper"
 	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel"
+)
+
+// Build information, set with
+// -ldflags "-X main.version=... -X main.commit=... -X main.buildTime=..."
+var (
+	version   = "dev"
+	commit    = ""
+	buildTime = ""
 )
 
+// buildInfo falls back to the VCS data stamped by the go tool when the
+// ldflags were not set.
+func buildInfo() handler.BuildInfo {
+	info := handler.BuildInfo{
+		Version:   version,
+		Commit:    commit,
+		BuildTime: buildTime,
+		GoVersion: runtime.Version(),
+	}
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		for _, s := range bi.Settings {
+			switch {
+			case s.Key == "vcs.revision" && info.Commit == "":
+				info.Commit = s.Value
+			case s.Key == "vcs.time" && info.BuildTime == "":
+				info.BuildTime = s.Value
+			}
+		}
+	}
+	return info
+}
+
+// rateLimitRules converts the rate limit config for handler.RateLimit.
+func rateLimitRules(cfg config.RateLimitConfig) handler.RateLimitRules {
+	rule := func(r config.RateLimitRule) handler.RateLimitRule {
+		return handler.RateLimitRule{Burst: r.Burst, Every: r.Every, Key: r.Key}
+	}
+	rules := handler.RateLimitRules{
+		Default: rule(cfg.Default),
+		Routes:  make(map[string]handler.RateLimitRule, len(cfg.Routes)),
+	}
+	for path, r := range cfg.Routes {
+		rules.Routes[path] = rule(r)
+	}
+	return rules
+}
+
+// closer is a dependency that has to be released after the server stops.
+type closer interface {
+	Close(ctx context.Context) error
+}
+
+// closerFunc adapts shutdown functions such as TracerProvider.Shutdown.
+type closerFunc func(ctx context.Context) error
+
+func (f closerFunc) Close(ctx context.Context) error {
+	return f(ctx)
+}
+
 func main() {
-	log.Println("Starting application...")
+	os.Exit(run())
+}
+
+// run wires the application and blocks until it is stopped. The returned
+// exit code is non-zero when the server failed or could not drain cleanly.
+func run() int {
+	// Load settings once and hand them to the constructors (DI)
+	cfg, err := config.Load()
+	if err != nil {
+		slog.Error("failed to load config", "error", err)
+		return 1
+	}
+
+	// One JSON logger shared by every component (DI)
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(cfg.Log.Level)); err != nil {
+		slog.Error("invalid log level", "error", err)
+		return 1
+	}
+	// Records logged with a request context carry its request ID
+	logger := slog.New(requestid.LogHandler(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: level})))
+	slog.SetDefault(logger)
+
+	logger.Info("starting application", "version", version)
+
+	// Pick where validation errors are recorded (DI)
+	rotation := validatorwrapper.RotationOptions{
+		MaxSize:    int64(cfg.Validation.Rotation.MaxSizeMB) << 20,
+		Daily:      cfg.Validation.Rotation.Daily,
+		MaxBackups: cfg.Validation.Rotation.MaxBackups,
+		Compress:   cfg.Validation.Rotation.Compress,
+	}
+	sink, err := validatorwrapper.NewErrorSink(cfg.Validation.Sink, cfg.Validation.SinkPath, rotation)
+	if err != nil {
+		logger.Error("failed to create validation error sink", "error", err)
+		return 1
+	}
+
+	// Record validation errors off the request path
+	asyncSink := validatorwrapper.NewAsyncSink(sink, validatorwrapper.AsyncOptions{
+		BufferSize:    cfg.Validation.BufferSize,
+		BatchSize:     cfg.Validation.BatchSize,
+		FlushInterval: cfg.Validation.FlushInterval,
+		BlockWhenFull: cfg.Validation.BlockWhenFull,
+		OnError: func(err error) {
+			logger.Error("failed to record validation errors", "error", err)
+		},
+	})
+
+	// Dependencies closed in order once the server has drained
+	closers := []closer{asyncSink}
+
+	// Dependencies checked by the readiness probe
+	readyChecks := map[string]handler.Pinger{"validation_sink": asyncSink}
+
+	// Tracing shared by the middleware and the validator (DI)
+	tp, err := tracing.NewTracerProvider(context.Background(), cfg.Tracing.Exporter, cfg.Tracing.ServiceName)
+	if err != nil {
+		logger.Error("failed to create tracer provider", "error", err)
+		return 1
+	}
+	propagator := tracing.Propagator()
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagator)
+
+	// Prometheus metrics shared by the middleware and the validator (DI)
+	m := metrics.New()
+	m.Register(
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Name: "validation_records_dropped_total",
+			Help: "Validation error records dropped because the queue was full.",
+		}, func() float64 { return float64(asyncSink.Dropped()) }),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Name: "validation_records_failed_total",
+			Help: "Validation error records the sink failed to write.",
+		}, func() float64 { return float64(asyncSink.Failed()) }),
+	)
 
 	// Initialize validator once (DI)
 	v := validator.New(validator.WithRequiredStructEnabled())
-	vWrapper := validatorwrapper.NewValidatorWrapper(v)
+	vWrapper := validatorwrapper.NewValidatorWrapper(v, asyncSink, logger,
+		validatorwrapper.WithFailureRecorder(m),
+		validatorwrapper.WithTracerProvider(tp),
+	)
+
+	// Game state for the guess-cat endpoint (DI)
+	catNameStore := store.NewMemoryCatNameStore(cfg.Game.CatName)
+
+	// Repositories for favorite numbers and pet names (DI)
+	var favRepo handler.FavoriteRepo = store.NewMemoryFavoriteRepo()
+	var petRepo handler.PetRepo = store.NewMemoryPetRepo()
+	if cfg.Storage.Driver == "sqlite" {
+		db, err := store.NewSQLiteStore(cfg.Storage.Path)
+		if err != nil {
+			logger.Error("failed to open storage", "error", err)
+			return 1
+		}
+		favRepo, petRepo = db, db
+		closers = append(closers, db)
+		readyChecks["storage"] = db
+	}
 
 	// Initialize all handlers with the same validator instance (DI)
-	favHandler := handler.NewFavoriteNumHandler(vWrapper)
-	petNameHandler := handler.NewPetNameHandler(vWrapper)
-	thaiCIDHandler := handler.NewThaiCIDHandler(vWrapper)
-	guessCatHandler := handler.NewGuessCatNameHandler(vWrapper)
+	favHandler := handler.NewFavoriteNumHandler(vWrapper, favRepo, logger)
+	petNameHandler := handler.NewPetNameHandler(vWrapper, petRepo, logger)
+	thaiCIDHandler := handler.NewThaiCIDHandler(vWrapper, logger)
+	guessCatHandler := handler.NewGuessCatNameHandler(vWrapper, catNameStore, logger)
+	batchHandler := handler.NewBatchHandler(vWrapper, logger)
+	healthHandler := handler.NewHealthHandler(readyChecks, buildInfo(), logger)
+
+	// Recorded validation failures can only be queried from file sinks
+	var validationErrorsHandler *handler.ValidationErrorsHandler
+	if scanner, ok := sink.(validatorwrapper.RecordScanner); ok {
+		querier := validatorwrapper.NewErrorQuerier(scanner, cfg.Validation.QueryMaxRecords)
+		// Admin query mistakes are not product data, keep them out of the
+		// sink and the metrics
+		adminValidator := validatorwrapper.NewValidatorWrapper(
+			validator.New(validator.WithRequiredStructEnabled()),
+			validatorwrapper.NewNopSink(),
+			logger,
+		)
+		validationErrorsHandler = handler.NewValidationErrorsHandler(adminValidator, querier, logger)
+	}
+
+	// API description built from the same request structs as the handlers
+	spec, err := openapi.Generate(handler.Routes)
+	if err != nil {
+		logger.Error("failed to generate OpenAPI spec", "error", err)
+		return 1
+	}
+	specHandler, err := openapi.Handler(spec)
+	if err != nil {
+		logger.Error("failed to encode OpenAPI spec", "error", err)
+		return 1
+	}
 
 	// Setup Echo server
 	e := echo.New()
-	e.Use(middleware.Recover())
+	e.HideBanner = true
+	e.HidePort = true
+	e.HTTPErrorHandler = handler.NewHTTPErrorHandler(logger)
+	// Use the peer address, X-Forwarded-For and X-Real-IP are set by clients
+	// and would let them pick their rate limit key
+	e.IPExtractor = echo.ExtractIPDirect()
+
+	// Echo middlewares that can be enabled from config
+	middlewares := map[string]echo.MiddlewareFunc{
+		"requestid": requestid.Middleware(),
+		"recover":   handler.Recover(logger),
+		"tracing":   tracing.Middleware(tp, propagator),
+		"logger":    handler.RequestLogger(logger),
+		"gzip":      middleware.Gzip(),
+		"metrics":   m.Middleware(),
+	}
+	for _, name := range cfg.Server.Middlewares {
+		e.Use(middlewares[name])
+	}
+	// Innermost, so the middlewares above see the status of failed requests
+	e.Use(handler.WriteErrors())
 
-	// Register all routes
-	e.POST("/api/v1/favorite", favHandler.Favorite)
-	e.POST("/api/v1/pet-name", petNameHandler.ValidatePetName)
-	e.POST("/api/v1/thai-cid", thaiCIDHandler.ValidateThaiCID)
-	e.POST("/api/v1/guess-cat", guessCatHandler.GuessTheCatName)
+	// Throttle the public and admin APIs per client, probes are not
+	var limit []echo.MiddlewareFunc
+	if cfg.RateLimit.Enabled {
+		limit = append(limit, handler.RateLimit(store.NewMemoryRateLimitStore(), rateLimitRules(cfg.RateLimit), logger))
+	}
+
+	// Admin routes expose client IPs, they are off unless a token is set
+	admin := append(slices.Clip(limit), handler.AdminAuth(cfg.Admin.Token))
+	var listValidationErrors echo.HandlerFunc
+	if validationErrorsHandler != nil && cfg.Admin.Token != "" {
+		listValidationErrors = validationErrorsHandler.ListValidationErrors
+	}
+
+	// Register the documented routes, each needs a handler here
+	err = handler.RegisterRoutes(e, handler.Routes, map[string]echo.HandlerFunc{
+		"POST /api/v1/favorite":        favHandler.Favorite,
+		"GET /api/v1/favorite/:userId": favHandler.GetFavorite,
+		"POST /api/v1/pet-name":        petNameHandler.ValidatePetName,
+		"GET /api/v1/pets/:ownerId":    petNameHandler.ListPets,
+		"POST /api/v1/thai-cid":        thaiCIDHandler.ValidateThaiCID,
+		"POST /api/v1/guess-cat":       guessCatHandler.GuessTheCatName,
+		"POST /api/v1/batch/:kind":     batchHandler.ValidateBatch,
+		"GET /admin/validation-errors": listValidationErrors,
+		"GET /healthz":                 healthHandler.Healthz,
+		"GET /readyz":                  healthHandler.Readyz,
+		"GET /version":                 healthHandler.Version,
+	}, func(r handler.Route) []echo.MiddlewareFunc {
+		switch {
+		case strings.HasPrefix(r.Path, "/api/"):
+			return limit
+		case strings.HasPrefix(r.Path, "/admin/"):
+			return admin
+		}
+		return nil
+	})
+	if err != nil {
+		logger.Error("failed to register routes", "error", err)
+		return 1
+	}
+	// Not part of the API description
+	e.GET("/metrics", m.Handler())
+	e.GET("/openapi.json", specHandler)
+
+	// Shut tracing down last so spans ended while draining are still exported
+	closers = append(closers, closerFunc(tp.Shutdown))
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
 
-	if err := e.Start(":1323"); err != nil {
-		e.Logger.Error("failed to start server", "error", err)
+	exitCode := 0
+	serverErr := make(chan error, 1)
+	go func() {
+		logger.Info("server started", "addr", cfg.Server.Addr)
+		serverErr <- e.Start(cfg.Server.Addr)
+	}()
+
+	select {
+	case err := <-serverErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("failed to start server", "error", err)
+			exitCode = 1
+		}
+	case <-ctx.Done():
+		logger.Info("shutting down")
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
+
+	// Stop accepting new requests and wait for in-flight ones
+	if err := e.Shutdown(shutdownCtx); err != nil {
+		logger.Error("failed to drain server", "error", err)
+		exitCode = 1
 	}
+
+	for _, c := range closers {
+		if err := c.Close(shutdownCtx); err != nil {
+			logger.Error("failed to close dependency", "error", err)
+			exitCode = 1
+		}
+	}
+	logger.Info("stopped",
+		"validation_records_dropped", asyncSink.Dropped(),
+		"validation_records_failed", asyncSink.Failed(),
+	)
+
+	return exitCode
 }
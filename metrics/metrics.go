@@ -0,0 +1,86 @@
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// unmatchedRoute labels requests that did not hit any registered route,
+// so random paths cannot blow up the label cardinality.
+const unmatchedRoute = "unmatched"
+
+// Metrics owns a private registry with the request and validation metrics.
+type Metrics struct {
+	registry           *prometheus.Registry
+	requests           *prometheus.CounterVec
+	duration           *prometheus.HistogramVec
+	validationFailures *prometheus.CounterVec
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "HTTP requests by route, method and status code.",
+		}, []string{"route", "method", "status"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "HTTP request latency by route and method.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"route", "method"}),
+		validationFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "validation_failures_total",
+			Help: "Failed field validations by struct, field and tag.",
+		}, []string{"struct", "field", "tag"}),
+	}
+	m.registry.MustRegister(
+		m.requests,
+		m.duration,
+		m.validationFailures,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return m
+}
+
+// Register adds extra collectors, e.g. counters owned by other components.
+func (m *Metrics) Register(cs ...prometheus.Collector) {
+	m.registry.MustRegister(cs...)
+}
+
+// Middleware counts and times every request by its route template.
+func (m *Metrics) Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			err := next(c)
+
+			route := c.Path()
+			if route == "" || c.Response().Status == 404 {
+				route = unmatchedRoute
+			}
+			method := c.Request().Method
+			status := strconv.Itoa(c.Response().Status)
+
+			m.requests.WithLabelValues(route, method, status).Inc()
+			m.duration.WithLabelValues(route, method).Observe(time.Since(start).Seconds())
+			return err
+		}
+	}
+}
+
+// Handler serves the registry in the Prometheus text format.
+func (m *Metrics) Handler() echo.HandlerFunc {
+	return echo.WrapHandler(promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{}))
+}
+
+// RecordValidationFailure implements validatorwrapper.FailureRecorder.
+func (m *Metrics) RecordValidationFailure(structName, field, tag string) {
+	m.validationFailures.WithLabelValues(structName, field, tag).Inc()
+}
@@ -0,0 +1,84 @@
+package metrics
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/BoomNooB/medium-go-di/handler"
+	"github.com/BoomNooB/medium-go-di/validatorwrapper"
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type sampleRequest struct {
+	UserID string `json:"userId" validate:"required,uuid_rfc4122"`
+}
+
+func scrape(t *testing.T, e *echo.Echo) string {
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	return rec.Body.String()
+}
+
+func TestMiddleware_CountsRequestsByRoute(t *testing.T) {
+	m := New()
+	e := echo.New()
+	e.Use(m.Middleware(), handler.WriteErrors())
+	e.GET("/metrics", m.Handler())
+	e.POST("/api/v1/favorite/:id", func(c echo.Context) error {
+		return c.NoContent(http.StatusBadRequest)
+	})
+
+	for _, id := range []string{"a", "b"} {
+		e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/api/v1/favorite/"+id, nil))
+	}
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/random/path", nil))
+
+	body := scrape(t, e)
+	assert.Contains(t, body, `http_requests_total{method="POST",route="/api/v1/favorite/:id",status="400"} 2`)
+	assert.Contains(t, body, `http_requests_total{method="GET",route="unmatched",status="404"} 1`)
+	assert.Contains(t, body, `http_request_duration_seconds_count{method="POST",route="/api/v1/favorite/:id"} 2`)
+	assert.NotContains(t, body, "/random/path")
+}
+
+func TestMiddleware_CountsPanics(t *testing.T) {
+	m := New()
+	e := echo.New()
+	e.Use(m.Middleware(), handler.Recover(slog.New(slog.DiscardHandler)), handler.WriteErrors())
+	e.GET("/metrics", m.Handler())
+	e.GET("/panic", func(c echo.Context) error {
+		panic("boom")
+	})
+
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/panic", nil))
+
+	body := scrape(t, e)
+	assert.Contains(t, body, `http_requests_total{method="GET",route="/panic",status="500"} 1`)
+}
+
+func TestRecordValidationFailure_FromValidatorWrapper(t *testing.T) {
+	m := New()
+	vw := validatorwrapper.NewValidatorWrapper(
+		validator.New(validator.WithRequiredStructEnabled()),
+		validatorwrapper.NewNopSink(),
+		slog.New(slog.DiscardHandler),
+		validatorwrapper.WithFailureRecorder(m),
+	)
+	vw.StructValidation(context.Background(), &sampleRequest{UserID: "nope"})
+	vw.StructValidation(context.Background(), &sampleRequest{})
+
+	e := echo.New()
+	e.GET("/metrics", m.Handler())
+	body := scrape(t, e)
+
+	assert.Contains(t, body, `validation_failures_total{field="UserID",struct="sampleRequest",tag="uuid_rfc4122"} 1`)
+	assert.Contains(t, body, `validation_failures_total{field="UserID",struct="sampleRequest",tag="required"} 1`)
+	assert.True(t, strings.Contains(body, "go_goroutines"))
+}
@@ -0,0 +1,259 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/BoomNooB/medium-go-di/handler"
+	"github.com/BoomNooB/medium-go-di/jsontag"
+	"github.com/labstack/echo/v4"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	openAPIVersion = "3.1.0"
+	title          = "medium-go-di"
+	// apiVersion is the version of the API itself, bump it on breaking changes.
+	apiVersion = "1.0.0"
+
+	jsonContentType = "application/json"
+)
+
+// generatedHeader marks openapi.yaml as generated so it is not edited by hand.
+const generatedHeader = "# Code generated by go run ./cmd/openapi; DO NOT EDIT.\n"
+
+type Document struct {
+	OpenAPI    string                           `json:"openapi" yaml:"openapi"`
+	Info       Info                             `json:"info" yaml:"info"`
+	Paths      map[string]map[string]*Operation `json:"paths" yaml:"paths"`
+	Components Components                       `json:"components" yaml:"components"`
+}
+
+type Info struct {
+	Title   string `json:"title" yaml:"title"`
+	Version string `json:"version" yaml:"version"`
+}
+
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas" yaml:"schemas"`
+}
+
+type Operation struct {
+	Summary     string               `json:"summary,omitempty" yaml:"summary,omitempty"`
+	Parameters  []Parameter          `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty" yaml:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses" yaml:"responses"`
+}
+
+type Parameter struct {
+	Name     string  `json:"name" yaml:"name"`
+	In       string  `json:"in" yaml:"in"`
+	Required bool    `json:"required,omitempty" yaml:"required,omitempty"`
+	Schema   *Schema `json:"schema" yaml:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required" yaml:"required"`
+	Content  map[string]MediaType `json:"content" yaml:"content"`
+}
+
+type Response struct {
+	Description string               `json:"description" yaml:"description"`
+	Content     map[string]MediaType `json:"content,omitempty" yaml:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema" yaml:"schema"`
+}
+
+// pathParam matches the echo style path parameters such as :userId.
+var pathParam = regexp.MustCompile(`:([A-Za-z0-9_]+)`)
+
+// Generate builds the spec of routes, translating the validate tags of the
+// request structs into JSON Schema constraints.
+func Generate(routes []handler.Route) (*Document, error) {
+	g := &generator{schemas: map[string]*Schema{}}
+
+	responseSchema, err := g.schemaFor(reflect.TypeOf(handler.Response{}))
+	if err != nil {
+		return nil, err
+	}
+	problemSchema, err := g.schemaFor(reflect.TypeOf(handler.Problem{}))
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &Document{
+		OpenAPI:    openAPIVersion,
+		Info:       Info{Title: title, Version: apiVersion},
+		Paths:      map[string]map[string]*Operation{},
+		Components: Components{Schemas: g.schemas},
+	}
+	for _, r := range routes {
+		op, err := g.operation(r, responseSchema, problemSchema)
+		if err != nil {
+			return nil, fmt.Errorf("%s %s: %w", r.Method, r.Path, err)
+		}
+		path := pathParam.ReplaceAllString(r.Path, "{$1}")
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = map[string]*Operation{}
+		}
+		doc.Paths[path][strings.ToLower(r.Method)] = op
+	}
+	return doc, nil
+}
+
+// MarshalYAML encodes doc the way it is checked in as openapi.yaml.
+func MarshalYAML(doc *Document) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(generatedHeader)
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Handler serves doc as JSON. The document is encoded once up front.
+func Handler(doc *Document) (echo.HandlerFunc, error) {
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	return func(c echo.Context) error {
+		return c.JSONBlob(http.StatusOK, b)
+	}, nil
+}
+
+type generator struct {
+	schemas map[string]*Schema
+}
+
+func (g *generator) operation(r handler.Route, responseSchema, problemSchema *Schema) (*Operation, error) {
+	op := &Operation{
+		Summary:   r.Summary,
+		Responses: map[string]*Response{},
+	}
+
+	if r.Request != nil {
+		params, body, err := g.request(reflect.TypeOf(r.Request))
+		if err != nil {
+			return nil, err
+		}
+		op.Parameters = params
+		if body != nil {
+			op.RequestBody = &RequestBody{
+				Required: true,
+				Content:  map[string]MediaType{jsonContentType: {Schema: body}},
+			}
+		}
+	}
+
+	okContentType, okSchema := jsonContentType, responseSchema
+	switch {
+	case r.Stream:
+		// each line is validated against the request type picked by the path
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]MediaType{handler.MIMEApplicationNDJSON: {Schema: &Schema{Type: "object"}}},
+		}
+		line, err := g.schemaFor(reflect.TypeOf(r.Data))
+		if err != nil {
+			return nil, err
+		}
+		okContentType, okSchema = handler.MIMEApplicationNDJSON, line
+	case r.Data != nil:
+		data, err := g.schemaFor(reflect.TypeOf(r.Data))
+		if err != nil {
+			return nil, err
+		}
+		okSchema = &Schema{AllOf: []*Schema{
+			responseSchema,
+			{Type: "object", Properties: map[string]*Schema{"data": data}},
+		}}
+	}
+	op.Responses[strconv.Itoa(http.StatusOK)] = &Response{
+		Description: http.StatusText(http.StatusOK),
+		Content:     map[string]MediaType{okContentType: {Schema: okSchema}},
+	}
+	for _, code := range r.Errors {
+		op.Responses[strconv.Itoa(code)] = &Response{
+			Description: http.StatusText(code),
+			Content: map[string]MediaType{
+				jsonContentType:                    {Schema: responseSchema},
+				handler.MIMEApplicationProblemJSON: {Schema: problemSchema},
+			},
+		}
+	}
+	return op, nil
+}
+
+// request splits the fields of a request struct between path and query
+// parameters and the JSON body. body is nil when no field is bound from it.
+func (g *generator) request(t reflect.Type) ([]Parameter, *Schema, error) {
+	if t.Kind() != reflect.Struct {
+		return nil, nil, fmt.Errorf("request %s is not a struct", t)
+	}
+
+	var params []Parameter
+	hasBody := false
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		in, name := "", ""
+		if n := tagName(f.Tag.Get("param")); n != "" {
+			in, name = "path", n
+		} else if n := tagName(f.Tag.Get("query")); n != "" {
+			in, name = "query", n
+		}
+		if in == "" {
+			if jsontag.Name(f) != "" {
+				hasBody = true
+			}
+			continue
+		}
+
+		s, err := g.schemaFor(f.Type)
+		if err != nil {
+			return nil, nil, err
+		}
+		required := applyValidateTag(s, f.Type, f.Tag.Get("validate"))
+		params = append(params, Parameter{
+			Name:     name,
+			In:       in,
+			Required: in == "path" || required,
+			Schema:   s,
+		})
+	}
+
+	if !hasBody {
+		return params, nil, nil
+	}
+	body, err := g.schemaFor(t)
+	if err != nil {
+		return nil, nil, err
+	}
+	return params, body, nil
+}
+
+// tagName returns the name part of a json, param or query tag, or "" when
+// the field is not bound by it.
+func tagName(tag string) string {
+	name, _, _ := strings.Cut(tag, ",")
+	if name == "-" {
+		return ""
+	}
+	return name
+}
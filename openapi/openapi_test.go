@@ -0,0 +1,90 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/BoomNooB/medium-go-di/handler"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpecIsUpToDate(t *testing.T) {
+	doc, err := Generate(handler.Routes)
+	require.NoError(t, err)
+	want, err := MarshalYAML(doc)
+	require.NoError(t, err)
+
+	got, err := os.ReadFile("../openapi.yaml")
+	require.NoError(t, err)
+	assert.True(t, bytes.Equal(want, got), "openapi.yaml is stale, run go generate ./...")
+}
+
+type tagsRequest struct {
+	ID      string   `param:"id" validate:"required,uuid_rfc4122"`
+	Page    int      `query:"page" validate:"omitempty,gte=1"`
+	Code    string   `json:"code" validate:"required,len=13,numeric"`
+	Name    string   `json:"name" validate:"min=2,max=50"`
+	Count   int      `json:"count" validate:"gt=0,lte=10"`
+	Tags    []string `json:"tags" validate:"max=3,dive,min=1"`
+	Kind    string   `json:"kind" validate:"oneof=cat dog"`
+	CID     string   `json:"cid" validate:"thai_cid"`
+	Ignored string   `json:"-"`
+}
+
+func intPtr(n int) *int           { return &n }
+func floatPtr(n float64) *float64 { return &n }
+
+func TestGenerate_TranslatesValidateTags(t *testing.T) {
+	doc, err := Generate([]handler.Route{{
+		Method:  http.MethodPut,
+		Path:    "/things/:id",
+		Request: tagsRequest{},
+		Errors:  []int{http.StatusBadRequest},
+	}})
+	require.NoError(t, err)
+
+	op := doc.Paths["/things/{id}"]["put"]
+	require.NotNil(t, op)
+	assert.Equal(t, []Parameter{
+		{Name: "id", In: "path", Required: true, Schema: &Schema{Type: "string", Format: "uuid"}},
+		{Name: "page", In: "query", Schema: &Schema{Type: "integer", Minimum: floatPtr(1)}},
+	}, op.Parameters)
+	assert.Equal(t, "#/components/schemas/tagsRequest", op.RequestBody.Content[jsonContentType].Schema.Ref)
+	assert.Contains(t, op.Responses, "200")
+	assert.Contains(t, op.Responses, "400")
+
+	s := doc.Components.Schemas["tagsRequest"]
+	require.NotNil(t, s)
+	assert.Equal(t, []string{"code"}, s.Required)
+	assert.Equal(t, map[string]*Schema{
+		"code":  {Type: "string", MinLength: intPtr(13), MaxLength: intPtr(13), Pattern: numericPattern},
+		"name":  {Type: "string", MinLength: intPtr(2), MaxLength: intPtr(50)},
+		"count": {Type: "integer", ExclusiveMinimum: floatPtr(0), Maximum: floatPtr(10)},
+		"tags":  {Type: "array", MaxItems: intPtr(3), Items: &Schema{Type: "string", MinLength: intPtr(1)}},
+		"kind":  {Type: "string", Enum: []string{"cat", "dog"}},
+		"cid":   {Type: "string", Validate: []string{"thai_cid"}},
+	}, s.Properties)
+}
+
+func TestHandler(t *testing.T) {
+	doc, err := Generate(handler.Routes)
+	require.NoError(t, err)
+	h, err := Handler(doc)
+	require.NoError(t, err)
+
+	e := echo.New()
+	rec := httptest.NewRecorder()
+	require.NoError(t, h(e.NewContext(httptest.NewRequest(http.MethodGet, "/openapi.json", nil), rec)))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var got map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+	assert.Equal(t, openAPIVersion, got["openapi"])
+	assert.Contains(t, got["paths"], "/api/v1/favorite/{userId}")
+}
@@ -0,0 +1,231 @@
+package openapi
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BoomNooB/medium-go-di/jsontag"
+)
+
+// numericPattern is the regular expression behind the numeric validate tag.
+const numericPattern = `^[-+]?[0-9]+(?:\.[0-9]+)?$`
+
+// Schema is the subset of JSON Schema used by the spec.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty" yaml:"$ref,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty" yaml:"allOf,omitempty"`
+	Type                 string             `json:"type,omitempty" yaml:"type,omitempty"`
+	Format               string             `json:"format,omitempty" yaml:"format,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty" yaml:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty" yaml:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty" yaml:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty" yaml:"additionalProperties,omitempty"`
+	Enum                 []string           `json:"enum,omitempty" yaml:"enum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty" yaml:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty" yaml:"maxLength,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty" yaml:"minimum,omitempty"`
+	ExclusiveMinimum     *float64           `json:"exclusiveMinimum,omitempty" yaml:"exclusiveMinimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty" yaml:"maximum,omitempty"`
+	ExclusiveMaximum     *float64           `json:"exclusiveMaximum,omitempty" yaml:"exclusiveMaximum,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty" yaml:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty" yaml:"maxItems,omitempty"`
+	Pattern              string             `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+	// Validate keeps the validate rules that have no JSON Schema
+	// equivalent, such as thai_cid.
+	Validate []string `json:"x-validate,omitempty" yaml:"x-validate,omitempty"`
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// schemaFor returns the schema of t. Named structs are added to the
+// components once and referenced from everywhere else.
+func (g *generator) schemaFor(t reflect.Type) (*Schema, error) {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == timeType {
+		return &Schema{Type: "string", Format: "date-time"}, nil
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string"}, nil
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}, nil
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}, nil
+	case reflect.Interface:
+		// any value
+		return &Schema{}, nil
+	case reflect.Slice, reflect.Array:
+		items, err := g.schemaFor(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: "array", Items: items}, nil
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("map key of %s is not a string", t)
+		}
+		values, err := g.schemaFor(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: "object", AdditionalProperties: values}, nil
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		ref := &Schema{Ref: "#/components/schemas/" + t.Name()}
+		if _, ok := g.schemas[t.Name()]; ok {
+			return ref, nil
+		}
+		// Register before walking the fields so recursive types terminate
+		g.schemas[t.Name()] = &Schema{}
+		s, err := g.structSchema(t)
+		if err != nil {
+			return nil, err
+		}
+		g.schemas[t.Name()] = s
+		return ref, nil
+	default:
+		return nil, fmt.Errorf("unsupported type %s", t)
+	}
+}
+
+// structSchema describes the JSON fields of t. Fields bound from the path
+// or the query string are left to the parameters.
+func (g *generator) structSchema(t reflect.Type) (*Schema, error) {
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() || f.Tag.Get("param") != "" || f.Tag.Get("query") != "" {
+			continue
+		}
+		_, hasTag := f.Tag.Lookup("json")
+		if f.Anonymous && !hasTag && f.Type.Kind() == reflect.Struct {
+			// encoding/json inlines the fields of embedded structs
+			embedded, err := g.structSchema(f.Type)
+			if err != nil {
+				return nil, err
+			}
+			for name, fs := range embedded.Properties {
+				s.Properties[name] = fs
+			}
+			s.Required = append(s.Required, embedded.Required...)
+			continue
+		}
+		name := jsontag.Name(f)
+		if name == "" {
+			continue
+		}
+
+		fs, err := g.schemaFor(f.Type)
+		if err != nil {
+			return nil, fmt.Errorf("field %s.%s: %w", t.Name(), f.Name, err)
+		}
+		if applyValidateTag(fs, f.Type, f.Tag.Get("validate")) {
+			s.Required = append(s.Required, name)
+		}
+		s.Properties[name] = fs
+	}
+	return s, nil
+}
+
+// applyValidateTag translates the validate rules of a field into
+// constraints on s and reports whether the field is required.
+func applyValidateTag(s *Schema, t reflect.Type, tag string) bool {
+	if tag == "" {
+		return false
+	}
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	required := false
+	rules := strings.Split(tag, ",")
+	for i, rule := range rules {
+		name, param, _ := strings.Cut(rule, "=")
+		switch name {
+		case "required":
+			required = true
+		case "omitempty":
+		case "dive":
+			// The remaining rules apply to the elements
+			if s.Items != nil {
+				applyValidateTag(s.Items, t.Elem(), strings.Join(rules[i+1:], ","))
+			}
+			return required
+		case "min", "max", "len", "gt", "gte", "lt", "lte":
+			if !applyBound(s, t.Kind(), name, param) {
+				s.Validate = append(s.Validate, rule)
+			}
+		case "uuid", "uuid4", "uuid_rfc4122", "uuid4_rfc4122":
+			s.Format = "uuid"
+		case "numeric":
+			s.Pattern = numericPattern
+		case "oneof":
+			s.Enum = strings.Fields(param)
+		default:
+			s.Validate = append(s.Validate, rule)
+		}
+	}
+	return required
+}
+
+// applyBound sets the length, item count or value bound matching the kind
+// of the field. It returns false when the rule cannot be expressed.
+func applyBound(s *Schema, kind reflect.Kind, name, param string) bool {
+	n, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return false
+	}
+
+	switch kind {
+	case reflect.String, reflect.Slice, reflect.Array:
+		minLen, maxLen := &s.MinLength, &s.MaxLength
+		if kind != reflect.String {
+			minLen, maxLen = &s.MinItems, &s.MaxItems
+		}
+		count := int(n)
+		switch name {
+		case "min", "gte":
+			*minLen = &count
+		case "gt":
+			count++
+			*minLen = &count
+		case "max", "lte":
+			*maxLen = &count
+		case "lt":
+			count--
+			*maxLen = &count
+		case "len":
+			*minLen, *maxLen = &count, &count
+		}
+		return true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		switch name {
+		case "min", "gte":
+			s.Minimum = &n
+		case "gt":
+			s.ExclusiveMinimum = &n
+		case "max", "lte":
+			s.Maximum = &n
+		case "lt":
+			s.ExclusiveMaximum = &n
+		case "len":
+			s.Minimum, s.Maximum = &n, &n
+		}
+		return true
+	default:
+		return false
+	}
+}
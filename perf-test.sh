@@ -35,19 +35,28 @@ docker-compose down 2>/dev/null || true
 # Build and start the application
 echo "🏗️  Building and starting application in Docker..."
 echo "   (Limited to 64MB RAM, 0.25 CPU)"
+COMMIT=$(git rev-parse --short HEAD 2>/dev/null || echo unknown) \
+BUILD_TIME=$(date -u +%Y-%m-%dT%H:%M:%SZ) \
 docker-compose up --build -d
 
 # Wait for the application to be ready
 echo "⏳ Waiting for application to be ready..."
-sleep 3
-
-# Check if the application is responding
-if ! curl -s http://localhost:1323/api/v1/favorite > /dev/null 2>&1; then
-    echo "⚠️  Warning: Application might not be ready yet. Waiting a bit more..."
-    sleep 3
+READY=false
+for _ in $(seq 1 30); do
+    if curl -sf http://localhost:1323/readyz > /dev/null 2>&1; then
+        READY=true
+        break
+    fi
+    sleep 1
+done
+
+if [ "$READY" != "true" ]; then
+    echo "❌ Application did not become ready in 30s"
+    curl -s http://localhost:1323/readyz || true
+    exit 1
 fi
 
-echo "✅ Application is ready!"
+echo "✅ Application is ready! ($(curl -s http://localhost:1323/version))"
 echo ""
 
 # Show resource limits
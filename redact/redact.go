@@ -0,0 +1,128 @@
+package redact
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"strings"
+
+	"github.com/BoomNooB/medium-go-di/jsontag"
+)
+
+// TagName is the struct tag that marks personal data, e.g. `pii:"mask"`.
+const TagName = "pii"
+
+const (
+	// TagMask keeps only the last 4 characters, for IDs.
+	TagMask = "mask"
+	// TagHash replaces the value by a short hash, for names.
+	TagHash = "hash"
+)
+
+const visibleChars = 4
+
+// Mask replaces all but the last 4 characters with '*'.
+func Mask(s string) string {
+	r := []rune(s)
+	if len(r) <= visibleChars {
+		return strings.Repeat("*", len(r))
+	}
+	return strings.Repeat("*", len(r)-visibleChars) + string(r[len(r)-visibleChars:])
+}
+
+// Hash returns a short, stable SHA-256 digest so equal values can still be
+// correlated without being readable.
+func Hash(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return "sha256:" + hex.EncodeToString(sum[:8])
+}
+
+// Apply redacts value according to a pii tag value. Values without a known
+// tag are returned unchanged.
+func Apply(tag string, value any) any {
+	switch tag {
+	case TagMask:
+		return Mask(fmt.Sprint(value))
+	case TagHash:
+		return Hash(fmt.Sprint(value))
+	default:
+		return value
+	}
+}
+
+// FieldTag returns the pii tag of the field reached by path from t, where
+// path holds Go field names like validator's StructNamespace without the
+// leading struct name. Slice and map indexes such as "Items[0]" are ignored.
+func FieldTag(t reflect.Type, path []string) string {
+	for i, name := range path {
+		for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct {
+			return ""
+		}
+		name, _, _ = strings.Cut(name, "[")
+		f, ok := t.FieldByName(name)
+		if !ok {
+			return ""
+		}
+		if i == len(path)-1 {
+			return f.Tag.Get(TagName)
+		}
+		t = f.Type
+	}
+	return ""
+}
+
+// Struct wraps v so that logging it with slog prints its fields by json
+// name with every pii tagged field redacted.
+func Struct(v any) slog.LogValuer {
+	return structValue{v: v}
+}
+
+type structValue struct {
+	v any
+}
+
+func (s structValue) LogValue() slog.Value {
+	return structLogValue(reflect.ValueOf(s.v))
+}
+
+func structLogValue(rv reflect.Value) slog.Value {
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return slog.AnyValue(nil)
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return slog.AnyValue(rv.Interface())
+	}
+
+	rt := rv.Type()
+	attrs := make([]slog.Attr, 0, rt.NumField())
+	for i := 0; i < rt.NumField(); i++ {
+		f := rt.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name := jsontag.Name(f)
+		if name == "" {
+			continue
+		}
+
+		fv := rv.Field(i)
+		if tag := f.Tag.Get(TagName); tag != "" {
+			attrs = append(attrs, slog.Any(name, Apply(tag, fv.Interface())))
+			continue
+		}
+		if fv.Kind() == reflect.Struct || (fv.Kind() == reflect.Pointer && fv.Type().Elem().Kind() == reflect.Struct) {
+			attrs = append(attrs, slog.Attr{Key: name, Value: structLogValue(fv)})
+			continue
+		}
+		attrs = append(attrs, slog.Any(name, fv.Interface()))
+	}
+	return slog.GroupValue(attrs...)
+}
@@ -0,0 +1,76 @@
+package redact
+
+import (
+	"bytes"
+	"log/slog"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type person struct {
+	CitizenID string `json:"citizenId" pii:"mask"`
+	FullName  string `json:"fullName" pii:"hash"`
+	Age       int    `json:"age"`
+	Address   address
+	secret    string
+}
+
+type address struct {
+	Street string `json:"street" pii:"hash"`
+	City   string `json:"city"`
+}
+
+func TestMask(t *testing.T) {
+	assert.Equal(t, "*********0121", Mask("1234567890121"))
+	assert.Equal(t, "***", Mask("abc"))
+	assert.Equal(t, "", Mask(""))
+}
+
+func TestHash(t *testing.T) {
+	h := Hash("John Doe")
+	assert.True(t, strings.HasPrefix(h, "sha256:"))
+	assert.Equal(t, h, Hash("John Doe"))
+	assert.NotEqual(t, h, Hash("Jane Doe"))
+	assert.NotContains(t, h, "John")
+}
+
+func TestApply(t *testing.T) {
+	assert.Equal(t, "*********0121", Apply(TagMask, "1234567890121"))
+	assert.Equal(t, Hash("John"), Apply(TagHash, "John"))
+	assert.Equal(t, 42, Apply("", 42))
+}
+
+func TestFieldTag(t *testing.T) {
+	typ := reflect.TypeOf(&person{})
+	assert.Equal(t, TagMask, FieldTag(typ, []string{"CitizenID"}))
+	assert.Equal(t, TagHash, FieldTag(typ, []string{"Address", "Street"}))
+	assert.Equal(t, "", FieldTag(typ, []string{"Age"}))
+	assert.Equal(t, "", FieldTag(typ, []string{"Missing"}))
+}
+
+func TestStruct_LogValue(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	p := &person{
+		CitizenID: "1234567890121",
+		FullName:  "John Doe",
+		Age:       30,
+		Address:   address{Street: "1 Sukhumvit Rd", City: "Bangkok"},
+		secret:    "hidden",
+	}
+	logger.Info("test", "person", Struct(p))
+
+	out := buf.String()
+	assert.NotContains(t, out, "1234567890121")
+	assert.NotContains(t, out, "John Doe")
+	assert.NotContains(t, out, "Sukhumvit")
+	assert.NotContains(t, out, "hidden")
+	assert.Contains(t, out, `"citizenId":"*********0121"`)
+	assert.Contains(t, out, `"fullName":"`+Hash("John Doe")+`"`)
+	assert.Contains(t, out, `"age":30`)
+	assert.Contains(t, out, `"city":"Bangkok"`)
+}
@@ -0,0 +1,97 @@
+// Package requestid correlates the logs, responses and validation records
+// of one HTTP request.
+package requestid
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// maxLength bounds the IDs accepted from clients.
+const maxLength = 128
+
+// logKey is the attribute added to log records by LogHandler.
+const logKey = "request_id"
+
+type ctxKey struct{}
+
+// NewContext returns ctx carrying the request ID id.
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, id)
+}
+
+// FromContext returns the request ID of ctx, or "" when there is none.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(ctxKey{}).(string)
+	return id
+}
+
+// Middleware keeps the X-Request-ID sent by the client, or generates a
+// UUID when it is missing or not a plausible ID. The ID is returned in the
+// X-Request-ID response header and stored in the request context.
+func Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			id := req.Header.Get(echo.HeaderXRequestID)
+			if !valid(id) {
+				id = uuid.NewString()
+			}
+			c.Response().Header().Set(echo.HeaderXRequestID, id)
+			c.SetRequest(req.WithContext(NewContext(req.Context(), id)))
+			return next(c)
+		}
+	}
+}
+
+// valid accepts IDs made of printable ASCII, so a client cannot inject
+// control characters into the logs or the CSV sink.
+func valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < '!' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+type logHandler struct {
+	slog.Handler
+}
+
+// LogHandler adds the request ID of the context to every record logged
+// with one, unless the record already has a request_id attribute.
+func LogHandler(h slog.Handler) slog.Handler {
+	return logHandler{Handler: h}
+}
+
+func (h logHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := FromContext(ctx); id != "" && !hasAttr(r, logKey) {
+		r = r.Clone()
+		r.AddAttrs(slog.String(logKey, id))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h logHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return logHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h logHandler) WithGroup(name string) slog.Handler {
+	return logHandler{Handler: h.Handler.WithGroup(name)}
+}
+
+func hasAttr(r slog.Record, key string) bool {
+	found := false
+	r.Attrs(func(a slog.Attr) bool {
+		found = a.Key == key
+		return !found
+	})
+	return found
+}
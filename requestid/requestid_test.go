@@ -0,0 +1,93 @@
+package requestid
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// serve runs the middleware and returns the request ID seen by the handler.
+func serve(t *testing.T, incoming string) (*httptest.ResponseRecorder, string) {
+	t.Helper()
+	e := echo.New()
+	var seen string
+	e.Use(Middleware())
+	e.GET("/", func(c echo.Context) error {
+		seen = FromContext(c.Request().Context())
+		return c.NoContent(http.StatusNoContent)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if incoming != "" {
+		req.Header.Set(echo.HeaderXRequestID, incoming)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec, seen
+}
+
+func TestMiddleware_KeepsIncomingID(t *testing.T) {
+	rec, seen := serve(t, "req-123")
+
+	assert.Equal(t, "req-123", seen)
+	assert.Equal(t, "req-123", rec.Header().Get(echo.HeaderXRequestID))
+}
+
+func TestMiddleware_GeneratesID(t *testing.T) {
+	tests := []struct {
+		name     string
+		incoming string
+	}{
+		{name: "missing", incoming: ""},
+		{name: "control characters", incoming: "req\x1b[31m"},
+		{name: "spaces", incoming: "req 123"},
+		{name: "too long", incoming: strings.Repeat("a", maxLength+1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, seen := serve(t, tt.incoming)
+
+			_, err := uuid.Parse(seen)
+			assert.NoError(t, err)
+			assert.Equal(t, seen, rec.Header().Get(echo.HeaderXRequestID))
+		})
+	}
+}
+
+func TestFromContext_Missing(t *testing.T) {
+	assert.Empty(t, FromContext(context.Background()))
+}
+
+func TestLogHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(LogHandler(slog.NewJSONHandler(&buf, nil))).With("component", "test")
+	ctx := NewContext(context.Background(), "req-123")
+
+	logger.InfoContext(ctx, "with context")
+	logger.InfoContext(ctx, "explicit", "request_id", "other")
+	logger.Info("without context")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 3)
+	var got []map[string]any
+	for _, l := range lines {
+		var line map[string]any
+		require.NoError(t, json.Unmarshal([]byte(l), &line))
+		got = append(got, line)
+	}
+	assert.Equal(t, "req-123", got[0]["request_id"])
+	assert.Equal(t, "test", got[0]["component"])
+	assert.Equal(t, "other", got[1]["request_id"])
+	assert.NotContains(t, got[2], "request_id")
+	assert.Equal(t, 1, strings.Count(lines[1], "request_id"))
+}
@@ -0,0 +1,106 @@
+package store
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	// attemptsTTL is how long the attempts of a player are remembered after
+	// their last guess.
+	attemptsTTL = 24 * time.Hour
+	// maxPlayers bounds the memory used by the attempts, the players who
+	// guessed least recently are forgotten first.
+	maxPlayers = 10000
+)
+
+type playerAttempts struct {
+	userID string
+	count  int
+	won    bool
+	last   time.Time
+}
+
+type memoryCatNameStore struct {
+	mu      sync.Mutex
+	catName string
+	// players is ordered by last guess, most recent first, and indexed
+	// by userID.
+	players *list.List
+	byUser  map[string]*list.Element
+	now     func() time.Time
+}
+
+// NewMemoryCatNameStore keeps the secret cat name and each player's
+// attempts in memory. State is lost on restart, and attempts are forgotten
+// after attemptsTTL or once maxPlayers other players guessed since.
+func NewMemoryCatNameStore(catName string) *memoryCatNameStore {
+	return &memoryCatNameStore{
+		mu:      sync.Mutex{},
+		catName: catName,
+		players: list.New(),
+		byUser:  map[string]*list.Element{},
+		now:     time.Now,
+	}
+}
+
+func (s *memoryCatNameStore) CatName(ctx context.Context) (string, error) {
+	return s.catName, nil
+}
+
+func (s *memoryCatNameStore) IncrementAttempts(ctx context.Context, userID string) (int, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p := s.player(userID)
+	if !p.won {
+		p.count++
+	}
+	return p.count, p.won, nil
+}
+
+func (s *memoryCatNameStore) RecordWin(ctx context.Context, userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.player(userID).won = true
+	return nil
+}
+
+// player returns the attempts of userID, starting them if needed, and
+// marks the player as the most recent one.
+func (s *memoryCatNameStore) player(userID string) *playerAttempts {
+	now := s.now()
+	s.expire(now)
+
+	el, ok := s.byUser[userID]
+	if !ok {
+		if s.players.Len() >= maxPlayers {
+			s.remove(s.players.Back())
+		}
+		el = s.players.PushFront(&playerAttempts{userID: userID})
+		s.byUser[userID] = el
+	}
+	s.players.MoveToFront(el)
+
+	p := el.Value.(*playerAttempts)
+	p.last = now
+	return p
+}
+
+// expire forgets the players whose last guess is older than attemptsTTL.
+func (s *memoryCatNameStore) expire(now time.Time) {
+	for el := s.players.Back(); el != nil; el = s.players.Back() {
+		if now.Sub(el.Value.(*playerAttempts).last) < attemptsTTL {
+			return
+		}
+		s.remove(el)
+	}
+}
+
+func (s *memoryCatNameStore) remove(el *list.Element) {
+	s.players.Remove(el)
+	delete(s.byUser, el.Value.(*playerAttempts).userID)
+}
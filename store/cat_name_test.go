@@ -0,0 +1,99 @@
+package store
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryCatNameStore(t *testing.T) {
+	s := NewMemoryCatNameStore("Mittens")
+	ctx := context.Background()
+
+	name, err := s.CatName(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "Mittens", name)
+
+	n, _, _ := s.IncrementAttempts(ctx, "a")
+	assert.Equal(t, 1, n)
+	n, _, _ = s.IncrementAttempts(ctx, "a")
+	assert.Equal(t, 2, n)
+	n, _, _ = s.IncrementAttempts(ctx, "b")
+	assert.Equal(t, 1, n)
+}
+
+func TestMemoryCatNameStore_RecordWin(t *testing.T) {
+	s := NewMemoryCatNameStore("Mittens")
+	ctx := context.Background()
+
+	s.IncrementAttempts(ctx, "a")
+	assert.NoError(t, s.RecordWin(ctx, "a"))
+
+	n, won, err := s.IncrementAttempts(ctx, "a")
+	assert.NoError(t, err)
+	assert.True(t, won)
+	assert.Equal(t, 1, n, "attempts are not counted once won")
+
+	n, won, _ = s.IncrementAttempts(ctx, "b")
+	assert.False(t, won)
+	assert.Equal(t, 1, n)
+}
+
+func TestMemoryCatNameStore_Concurrent(t *testing.T) {
+	s := NewMemoryCatNameStore("Mittens")
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.IncrementAttempts(context.Background(), "a")
+		}()
+	}
+	wg.Wait()
+
+	n, _, _ := s.IncrementAttempts(context.Background(), "a")
+	assert.Equal(t, 51, n)
+}
+
+func TestMemoryCatNameStore_ForgetsIdlePlayers(t *testing.T) {
+	s := NewMemoryCatNameStore("Mittens")
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	s.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	s.IncrementAttempts(ctx, "a")
+	s.IncrementAttempts(ctx, "a")
+	now = now.Add(attemptsTTL - time.Second)
+	s.IncrementAttempts(ctx, "b")
+	now = now.Add(time.Second)
+
+	// "a" has been idle for attemptsTTL, "b" has not
+	n, _, _ := s.IncrementAttempts(ctx, "a")
+	assert.Equal(t, 1, n)
+	n, _, _ = s.IncrementAttempts(ctx, "b")
+	assert.Equal(t, 2, n)
+}
+
+func TestMemoryCatNameStore_BoundsPlayers(t *testing.T) {
+	s := NewMemoryCatNameStore("Mittens")
+	ctx := context.Background()
+
+	s.IncrementAttempts(ctx, "first")
+	s.IncrementAttempts(ctx, "kept")
+	for i := 0; i < maxPlayers-2; i++ {
+		s.IncrementAttempts(ctx, strconv.Itoa(i))
+	}
+	// guessing again makes "kept" the most recent player
+	s.IncrementAttempts(ctx, "kept")
+	s.IncrementAttempts(ctx, "new")
+
+	assert.Equal(t, maxPlayers, len(s.byUser))
+	assert.Equal(t, maxPlayers, s.players.Len())
+	assert.NotContains(t, s.byUser, "first")
+	n, _, _ := s.IncrementAttempts(ctx, "kept")
+	assert.Equal(t, 3, n)
+}
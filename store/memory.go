@@ -0,0 +1,134 @@
+package store
+
+import (
+	"container/list"
+	"context"
+	"slices"
+	"sync"
+)
+
+const (
+	// maxFavorites and maxOwners bound the memory used by the memory repos,
+	// the users read or written least recently are forgotten first.
+	maxFavorites = 10000
+	maxOwners    = 10000
+	// maxPetsPerOwner keeps the most recently added pet names of an owner.
+	maxPetsPerOwner = 20
+)
+
+// lru keeps up to max values by key, forgetting the least recently used
+// one first. It is not safe for concurrent use, callers hold their own lock.
+type lru[V any] struct {
+	max int
+	// entries is ordered by last use, most recent first, and indexed by key.
+	entries *list.List
+	byKey   map[string]*list.Element
+}
+
+type lruEntry[V any] struct {
+	key   string
+	value V
+}
+
+func newLRU[V any](max int) *lru[V] {
+	return &lru[V]{
+		max:     max,
+		entries: list.New(),
+		byKey:   map[string]*list.Element{},
+	}
+}
+
+func (c *lru[V]) get(key string) (V, bool) {
+	el, ok := c.byKey[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	c.entries.MoveToFront(el)
+	return el.Value.(*lruEntry[V]).value, true
+}
+
+func (c *lru[V]) put(key string, value V) {
+	if el, ok := c.byKey[key]; ok {
+		el.Value.(*lruEntry[V]).value = value
+		c.entries.MoveToFront(el)
+		return
+	}
+	if c.entries.Len() >= c.max {
+		oldest := c.entries.Back()
+		c.entries.Remove(oldest)
+		delete(c.byKey, oldest.Value.(*lruEntry[V]).key)
+	}
+	c.byKey[key] = c.entries.PushFront(&lruEntry[V]{key: key, value: value})
+}
+
+type memoryFavoriteRepo struct {
+	mu        sync.Mutex
+	favorites *lru[int]
+}
+
+// NewMemoryFavoriteRepo keeps favorite numbers in memory, mainly for tests.
+// Only the maxFavorites most recently used users are kept.
+func NewMemoryFavoriteRepo() *memoryFavoriteRepo {
+	return &memoryFavoriteRepo{
+		mu:        sync.Mutex{},
+		favorites: newLRU[int](maxFavorites),
+	}
+}
+
+func (r *memoryFavoriteRepo) SaveFavorite(ctx context.Context, userID string, favNum int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.favorites.put(userID, favNum)
+	return nil
+}
+
+func (r *memoryFavoriteRepo) GetFavorite(ctx context.Context, userID string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	favNum, ok := r.favorites.get(userID)
+	if !ok {
+		return 0, ErrNotFound
+	}
+	return favNum, nil
+}
+
+type memoryPetRepo struct {
+	mu   sync.Mutex
+	pets *lru[[]string]
+}
+
+// NewMemoryPetRepo keeps pet names in memory, mainly for tests. Only the
+// maxOwners most recently used owners are kept, each with up to
+// maxPetsPerOwner names.
+func NewMemoryPetRepo() *memoryPetRepo {
+	return &memoryPetRepo{
+		mu:   sync.Mutex{},
+		pets: newLRU[[]string](maxOwners),
+	}
+}
+
+func (r *memoryPetRepo) AddPet(ctx context.Context, ownerID, petName string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	pets, _ := r.pets.get(ownerID)
+	if slices.Contains(pets, petName) {
+		return nil
+	}
+	if len(pets) >= maxPetsPerOwner {
+		pets = pets[len(pets)-maxPetsPerOwner+1:]
+	}
+	r.pets.put(ownerID, append(pets, petName))
+	return nil
+}
+
+func (r *memoryPetRepo) ListPets(ctx context.Context, ownerID string) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	pets, _ := r.pets.get(ownerID)
+	return append([]string{}, pets...), nil
+}
@@ -0,0 +1,75 @@
+package store
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often full buckets are dropped, so keys seen once do
+// not stay in memory.
+const sweepInterval = time.Minute
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	// full is when the bucket is back to burst tokens.
+	full time.Time
+}
+
+type memoryRateLimitStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewMemoryRateLimitStore keeps token buckets in memory. Limits are per
+// instance, use a shared store when running several replicas.
+func NewMemoryRateLimitStore() *memoryRateLimitStore {
+	return &memoryRateLimitStore{
+		mu:        sync.Mutex{},
+		buckets:   map[string]*bucket{},
+		lastSweep: time.Now(),
+		now:       time.Now,
+	}
+}
+
+// Take removes a token from the bucket of key, which holds up to burst
+// tokens and gains one each every. When the bucket is empty it returns
+// false and the time until the next token.
+func (s *memoryRateLimitStore) Take(ctx context.Context, key string, burst int, every time.Duration) (bool, time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if now.Sub(s.lastSweep) >= sweepInterval {
+		s.sweep(now)
+	}
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(burst), last: now}
+		s.buckets[key] = b
+	}
+	b.tokens = min(float64(burst), b.tokens+float64(now.Sub(b.last))/float64(every))
+	b.last = now
+
+	if b.tokens < 1 {
+		return false, time.Duration((1 - b.tokens) * float64(every)), nil
+	}
+	b.tokens--
+	b.full = now.Add(time.Duration((float64(burst) - b.tokens) * float64(every)))
+	return true, 0, nil
+}
+
+// sweep drops the buckets that have refilled since their last use, they
+// behave like missing ones.
+func (s *memoryRateLimitStore) sweep(now time.Time) {
+	for key, b := range s.buckets {
+		if !now.Before(b.full) {
+			delete(s.buckets, key)
+		}
+	}
+	s.lastSweep = now
+}
@@ -0,0 +1,75 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestRateLimitStore() (*memoryRateLimitStore, *time.Time) {
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	s := NewMemoryRateLimitStore()
+	s.now = func() time.Time { return now }
+	s.lastSweep = now
+	return s, &now
+}
+
+func TestMemoryRateLimitStore_Take(t *testing.T) {
+	s, now := newTestRateLimitStore()
+	ctx := context.Background()
+
+	// the burst is available at once
+	for i := 0; i < 3; i++ {
+		ok, _, err := s.Take(ctx, "a", 3, time.Second)
+		assert.NoError(t, err)
+		assert.True(t, ok, i)
+	}
+	ok, retryAfter, _ := s.Take(ctx, "a", 3, time.Second)
+	assert.False(t, ok)
+	assert.Equal(t, time.Second, retryAfter)
+
+	// other keys have their own bucket
+	ok, _, _ = s.Take(ctx, "b", 3, time.Second)
+	assert.True(t, ok)
+
+	// one token comes back each second
+	*now = now.Add(600 * time.Millisecond)
+	ok, retryAfter, _ = s.Take(ctx, "a", 3, time.Second)
+	assert.False(t, ok)
+	assert.Equal(t, 400*time.Millisecond, retryAfter)
+	*now = now.Add(400 * time.Millisecond)
+	ok, _, _ = s.Take(ctx, "a", 3, time.Second)
+	assert.True(t, ok)
+}
+
+func TestMemoryRateLimitStore_RefillCapsAtBurst(t *testing.T) {
+	s, now := newTestRateLimitStore()
+	ctx := context.Background()
+	s.Take(ctx, "a", 2, time.Second)
+
+	*now = now.Add(time.Hour)
+	for i := 0; i < 2; i++ {
+		ok, _, _ := s.Take(ctx, "a", 2, time.Second)
+		assert.True(t, ok, i)
+	}
+	ok, _, _ := s.Take(ctx, "a", 2, time.Second)
+	assert.False(t, ok)
+}
+
+func TestMemoryRateLimitStore_SweepsFullBuckets(t *testing.T) {
+	s, now := newTestRateLimitStore()
+	ctx := context.Background()
+	s.Take(ctx, "fast", 1, time.Second)
+	s.Take(ctx, "slow", 1, time.Hour)
+
+	*now = now.Add(sweepInterval)
+	s.Take(ctx, "other", 1, time.Second)
+
+	// "fast" refilled long ago, "slow" still has to remember its empty bucket
+	assert.NotContains(t, s.buckets, "fast")
+	assert.Contains(t, s.buckets, "slow")
+	ok, _, _ := s.Take(ctx, "slow", 1, time.Hour)
+	assert.False(t, ok)
+}
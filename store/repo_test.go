@@ -0,0 +1,153 @@
+package store
+
+import (
+	"context"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type favoriteRepo interface {
+	SaveFavorite(ctx context.Context, userID string, favNum int) error
+	GetFavorite(ctx context.Context, userID string) (int, error)
+}
+
+type petRepo interface {
+	AddPet(ctx context.Context, ownerID, petName string) error
+	ListPets(ctx context.Context, ownerID string) ([]string, error)
+}
+
+func newSQLiteStore(t *testing.T) *sqliteStore {
+	s, err := NewSQLiteStore(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { s.Close(context.Background()) })
+	return s
+}
+
+func testFavoriteRepo(t *testing.T, r favoriteRepo) {
+	ctx := context.Background()
+
+	_, err := r.GetFavorite(ctx, "user-1")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	require.NoError(t, r.SaveFavorite(ctx, "user-1", 7))
+	require.NoError(t, r.SaveFavorite(ctx, "user-1", 42))
+	require.NoError(t, r.SaveFavorite(ctx, "user-2", 3))
+
+	favNum, err := r.GetFavorite(ctx, "user-1")
+	assert.NoError(t, err)
+	assert.Equal(t, 42, favNum)
+}
+
+func testPetRepo(t *testing.T, r petRepo) {
+	ctx := context.Background()
+
+	pets, err := r.ListPets(ctx, "owner-1")
+	assert.NoError(t, err)
+	assert.Empty(t, pets)
+	assert.NotNil(t, pets)
+
+	require.NoError(t, r.AddPet(ctx, "owner-1", "Fluffy"))
+	require.NoError(t, r.AddPet(ctx, "owner-1", "Buddy"))
+	require.NoError(t, r.AddPet(ctx, "owner-1", "Fluffy"))
+	require.NoError(t, r.AddPet(ctx, "owner-2", "Rex"))
+
+	pets, err = r.ListPets(ctx, "owner-1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Fluffy", "Buddy"}, pets)
+}
+
+func TestMemoryFavoriteRepo(t *testing.T) {
+	testFavoriteRepo(t, NewMemoryFavoriteRepo())
+}
+
+func TestMemoryPetRepo(t *testing.T) {
+	testPetRepo(t, NewMemoryPetRepo())
+}
+
+func TestSQLiteStore_Favorite(t *testing.T) {
+	testFavoriteRepo(t, newSQLiteStore(t))
+}
+
+func TestSQLiteStore_Pets(t *testing.T) {
+	testPetRepo(t, newSQLiteStore(t))
+}
+
+func TestSQLiteStore_Reopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.db")
+	s, err := NewSQLiteStore(path)
+	require.NoError(t, err)
+	require.NoError(t, s.SaveFavorite(context.Background(), "user-1", 9))
+	require.NoError(t, s.Close(context.Background()))
+
+	s, err = NewSQLiteStore(path)
+	require.NoError(t, err)
+	defer s.Close(context.Background())
+	favNum, err := s.GetFavorite(context.Background(), "user-1")
+	assert.NoError(t, err)
+	assert.Equal(t, 9, favNum)
+}
+
+func TestSQLiteStore_Ping(t *testing.T) {
+	s, err := NewSQLiteStore(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	assert.NoError(t, s.Ping(context.Background()))
+
+	require.NoError(t, s.Close(context.Background()))
+	assert.Error(t, s.Ping(context.Background()))
+}
+
+func TestMemoryFavoriteRepo_BoundsUsers(t *testing.T) {
+	r := NewMemoryFavoriteRepo()
+	ctx := context.Background()
+
+	r.SaveFavorite(ctx, "first", 1)
+	r.SaveFavorite(ctx, "kept", 2)
+	for i := 0; i < maxFavorites-2; i++ {
+		r.SaveFavorite(ctx, strconv.Itoa(i), i)
+	}
+	// reading "kept" makes it the most recently used user
+	r.GetFavorite(ctx, "kept")
+	r.SaveFavorite(ctx, "new", 3)
+
+	_, err := r.GetFavorite(ctx, "first")
+	assert.ErrorIs(t, err, ErrNotFound)
+	favNum, err := r.GetFavorite(ctx, "kept")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, favNum)
+	assert.Equal(t, maxFavorites, len(r.favorites.byKey))
+}
+
+func TestMemoryPetRepo_BoundsOwners(t *testing.T) {
+	r := NewMemoryPetRepo()
+	ctx := context.Background()
+
+	r.AddPet(ctx, "first", "Fluffy")
+	for i := 0; i < maxOwners; i++ {
+		r.AddPet(ctx, strconv.Itoa(i), "Rex")
+	}
+
+	pets, err := r.ListPets(ctx, "first")
+	assert.NoError(t, err)
+	assert.Empty(t, pets)
+	assert.Equal(t, maxOwners, len(r.pets.byKey))
+}
+
+func TestMemoryPetRepo_BoundsPetsPerOwner(t *testing.T) {
+	r := NewMemoryPetRepo()
+	ctx := context.Background()
+
+	for i := 0; i < maxPetsPerOwner+2; i++ {
+		r.AddPet(ctx, "owner", "pet-"+strconv.Itoa(i))
+	}
+
+	// the oldest names are dropped first
+	pets, err := r.ListPets(ctx, "owner")
+	assert.NoError(t, err)
+	assert.Len(t, pets, maxPetsPerOwner)
+	assert.Equal(t, "pet-2", pets[0])
+	assert.Equal(t, "pet-"+strconv.Itoa(maxPetsPerOwner+1), pets[len(pets)-1])
+}
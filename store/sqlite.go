@@ -0,0 +1,104 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	_ "modernc.org/sqlite"
+)
+
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS favorites (
+	user_id TEXT PRIMARY KEY,
+	fav_num INTEGER NOT NULL
+);
+CREATE TABLE IF NOT EXISTS pets (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	owner_id TEXT NOT NULL,
+	pet_name TEXT NOT NULL,
+	UNIQUE (owner_id, pet_name)
+);`
+
+type sqliteStore struct {
+	db *sql.DB
+}
+
+// NewSQLiteStore opens (or creates) the SQLite database at path. The
+// returned store implements both the favorite and the pet repositories.
+func NewSQLiteStore(path string) (*sqliteStore, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite: %w", err)
+	}
+	// SQLite allows a single writer, serialize access instead of retrying on SQLITE_BUSY
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("create sqlite schema: %w", err)
+	}
+	return &sqliteStore{db: db}, nil
+}
+
+// Ping checks that the database is still reachable.
+func (s *sqliteStore) Ping(ctx context.Context) error {
+	return s.db.PingContext(ctx)
+}
+
+func (s *sqliteStore) SaveFavorite(ctx context.Context, userID string, favNum int) error {
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO favorites (user_id, fav_num) VALUES (?, ?)
+		ON CONFLICT (user_id) DO UPDATE SET fav_num = excluded.fav_num`,
+		userID, favNum,
+	)
+	return err
+}
+
+func (s *sqliteStore) GetFavorite(ctx context.Context, userID string) (int, error) {
+	var favNum int
+	err := s.db.QueryRowContext(ctx,
+		`SELECT fav_num FROM favorites WHERE user_id = ?`,
+		userID,
+	).Scan(&favNum)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, ErrNotFound
+	}
+	return favNum, err
+}
+
+func (s *sqliteStore) AddPet(ctx context.Context, ownerID, petName string) error {
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO pets (owner_id, pet_name) VALUES (?, ?)
+		ON CONFLICT (owner_id, pet_name) DO NOTHING`,
+		ownerID, petName,
+	)
+	return err
+}
+
+func (s *sqliteStore) ListPets(ctx context.Context, ownerID string) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT pet_name FROM pets WHERE owner_id = ? ORDER BY id`,
+		ownerID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	pets := []string{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		pets = append(pets, name)
+	}
+	return pets, rows.Err()
+}
+
+// Close releases the database once the server has stopped.
+func (s *sqliteStore) Close(ctx context.Context) error {
+	return s.db.Close()
+}
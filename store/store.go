@@ -0,0 +1,7 @@
+package store
+
+import "errors"
+
+var (
+	ErrNotFound = errors.New("not found")
+)
@@ -0,0 +1,103 @@
+package tracing
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/BoomNooB/medium-go-di/handler"
+	"github.com/BoomNooB/medium-go-di/validatorwrapper"
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type sampleRequest struct {
+	Name string `json:"name" validate:"required,min=3"`
+}
+
+func newTestProvider() (*sdktrace.TracerProvider, *tracetest.InMemoryExporter) {
+	exp := tracetest.NewInMemoryExporter()
+	return sdktrace.NewTracerProvider(sdktrace.WithSyncer(exp)), exp
+}
+
+func attrValue(attrs []attribute.KeyValue, key string) attribute.Value {
+	for _, a := range attrs {
+		if string(a.Key) == key {
+			return a.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestMiddleware_ServerSpanWithValidationChild(t *testing.T) {
+	tp, exp := newTestProvider()
+	vw := validatorwrapper.NewValidatorWrapper(
+		validator.New(validator.WithRequiredStructEnabled()),
+		validatorwrapper.NewNopSink(),
+		slog.New(slog.DiscardHandler),
+		validatorwrapper.WithTracerProvider(tp),
+	)
+
+	e := echo.New()
+	e.Use(Middleware(tp, Propagator()))
+	e.POST("/api/v1/things/:id", func(c echo.Context) error {
+		vw.StructValidation(c.Request().Context(), &sampleRequest{Name: "ab"})
+		return c.NoContent(http.StatusBadRequest)
+	})
+
+	// W3C trace context sent by the caller
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	httpReq := httptest.NewRequest(http.MethodPost, "/api/v1/things/1", nil)
+	httpReq.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	e.ServeHTTP(httptest.NewRecorder(), httpReq)
+
+	spans := exp.GetSpans()
+	require.Len(t, spans, 2)
+	child, server := spans[0], spans[1]
+
+	assert.Equal(t, "POST /api/v1/things/:id", server.Name)
+	assert.Equal(t, trace.SpanKindServer, server.SpanKind)
+	assert.Equal(t, traceID, server.SpanContext.TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", server.Parent.SpanID().String())
+	assert.Equal(t, int64(http.StatusBadRequest), attrValue(server.Attributes, "http.response.status_code").AsInt64())
+
+	assert.Equal(t, "validatorwrapper.StructValidation", child.Name)
+	assert.Equal(t, server.SpanContext.SpanID(), child.Parent.SpanID())
+	assert.Equal(t, int64(1), attrValue(child.Attributes, "validation.field_errors").AsInt64())
+}
+
+func TestMiddleware_Panic(t *testing.T) {
+	tp, exp := newTestProvider()
+	e := echo.New()
+	e.Use(Middleware(tp, Propagator()), handler.Recover(slog.New(slog.DiscardHandler)), handler.WriteErrors())
+	e.GET("/panic", func(c echo.Context) error {
+		panic("boom")
+	})
+
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/panic", nil))
+
+	spans := exp.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, int64(http.StatusInternalServerError), attrValue(spans[0].Attributes, "http.response.status_code").AsInt64())
+	assert.Equal(t, codes.Error, spans[0].Status.Code)
+}
+
+func TestNewTracerProvider(t *testing.T) {
+	for _, exporter := range []string{ExporterNone, ExporterStdout, ExporterOTLP} {
+		tp, err := NewTracerProvider(context.Background(), exporter, "test")
+		require.NoError(t, err, exporter)
+		assert.NoError(t, tp.Shutdown(context.Background()), exporter)
+	}
+
+	_, err := NewTracerProvider(context.Background(), "jaeger", "test")
+	assert.Error(t, err)
+}
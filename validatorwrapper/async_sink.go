@@ -0,0 +1,196 @@
+package validatorwrapper
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	ErrSinkClosed = errors.New("error sink closed")
+)
+
+// AsyncOptions tunes NewAsyncSink. Zero values fall back to the defaults below.
+type AsyncOptions struct {
+	// BufferSize is the number of records that can wait in the queue.
+	BufferSize int
+	// BatchSize is the max number of records handed to the next sink at once.
+	BatchSize int
+	// FlushInterval bounds how long a partial batch waits before being written.
+	FlushInterval time.Duration
+	// BlockWhenFull makes Write wait for room instead of dropping records.
+	BlockWhenFull bool
+	// OnError receives failures of the next sink. By default they are
+	// logged with slog.Default.
+	OnError func(error)
+}
+
+const (
+	defaultAsyncBufferSize    = 1024
+	defaultAsyncBatchSize     = 128
+	defaultAsyncFlushInterval = time.Second
+)
+
+type asyncSink struct {
+	next          ErrorSink
+	queue         chan ErrorRecord
+	batchSize     int
+	flushInterval time.Duration
+	blockWhenFull bool
+	onError       func(error)
+
+	mu     sync.RWMutex
+	closed bool
+	// closing is closed first by Close, so that writers waiting for room
+	// give up and release mu.
+	closing   chan struct{}
+	closeOnce sync.Once
+	done      chan struct{}
+
+	dropped atomic.Uint64
+	failed  atomic.Uint64
+}
+
+// NewAsyncSink moves writes to next off the request path. Records are queued
+// on a bounded channel and written in batches by a background goroutine.
+// Close must be called to flush what is still queued.
+func NewAsyncSink(next ErrorSink, opts AsyncOptions) *asyncSink {
+	if opts.BufferSize <= 0 {
+		opts.BufferSize = defaultAsyncBufferSize
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = defaultAsyncBatchSize
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = defaultAsyncFlushInterval
+	}
+	if opts.OnError == nil {
+		opts.OnError = func(err error) {
+			slog.Default().Error("failed to record validation errors", "error", err)
+		}
+	}
+
+	s := &asyncSink{
+		next:          next,
+		queue:         make(chan ErrorRecord, opts.BufferSize),
+		batchSize:     opts.BatchSize,
+		flushInterval: opts.FlushInterval,
+		blockWhenFull: opts.BlockWhenFull,
+		onError:       opts.OnError,
+		closing:       make(chan struct{}),
+		done:          make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+func (s *asyncSink) Write(ctx context.Context, records []ErrorRecord) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return ErrSinkClosed
+	}
+
+	for i, r := range records {
+		if s.blockWhenFull {
+			select {
+			case s.queue <- r:
+			case <-ctx.Done():
+				s.dropped.Add(uint64(len(records) - i))
+				return ctx.Err()
+			case <-s.closing:
+				s.dropped.Add(uint64(len(records) - i))
+				return ErrSinkClosed
+			}
+			continue
+		}
+
+		select {
+		case s.queue <- r:
+		default:
+			s.dropped.Add(1)
+		}
+	}
+	return nil
+}
+
+// Close stops accepting records and waits until everything queued has been
+// handed to the next sink, or ctx is done. Writers blocked on a full queue
+// return ErrSinkClosed.
+func (s *asyncSink) Close(ctx context.Context) error {
+	s.closeOnce.Do(func() { close(s.closing) })
+
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.queue)
+	}
+	s.mu.Unlock()
+
+	select {
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Ping fails once the sink is closed and otherwise checks the next sink.
+func (s *asyncSink) Ping(ctx context.Context) error {
+	s.mu.RLock()
+	closed := s.closed
+	s.mu.RUnlock()
+	if closed {
+		return ErrSinkClosed
+	}
+	return PingSink(ctx, s.next)
+}
+
+// Dropped returns how many records were discarded because the queue was full.
+func (s *asyncSink) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Failed returns how many records the next sink failed to write.
+func (s *asyncSink) Failed() uint64 {
+	return s.failed.Load()
+}
+
+func (s *asyncSink) run() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]ErrorRecord, 0, s.batchSize)
+	for {
+		select {
+		case r, ok := <-s.queue:
+			if !ok {
+				s.flush(batch)
+				return
+			}
+			batch = append(batch, r)
+			if len(batch) >= s.batchSize {
+				s.flush(batch)
+				batch = batch[:0]
+			}
+		case <-ticker.C:
+			s.flush(batch)
+			batch = batch[:0]
+		}
+	}
+}
+
+func (s *asyncSink) flush(batch []ErrorRecord) {
+	if len(batch) == 0 {
+		return
+	}
+	if err := s.next.Write(context.Background(), batch); err != nil {
+		s.failed.Add(uint64(len(batch)))
+		s.onError(err)
+	}
+}
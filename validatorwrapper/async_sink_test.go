@@ -0,0 +1,164 @@
+package validatorwrapper
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingSink keeps every batch it receives. If gate is set, writes wait
+// until it is closed.
+type recordingSink struct {
+	mu      sync.Mutex
+	batches [][]ErrorRecord
+	gate    chan struct{}
+	err     error
+}
+
+func (r *recordingSink) Write(ctx context.Context, records []ErrorRecord) error {
+	if r.gate != nil {
+		<-r.gate
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.batches = append(r.batches, append([]ErrorRecord(nil), records...))
+	return r.err
+}
+
+func (r *recordingSink) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := 0
+	for _, b := range r.batches {
+		n += len(b)
+	}
+	return n
+}
+
+func makeRecords(n int) []ErrorRecord {
+	records := make([]ErrorRecord, n)
+	for i := range records {
+		records[i] = ErrorRecord{StructNamespace: "Req.Field", Tag: "required"}
+	}
+	return records
+}
+
+func TestAsyncSink_BatchesAndFlushesOnClose(t *testing.T) {
+	next := &recordingSink{}
+	s := NewAsyncSink(next, AsyncOptions{BatchSize: 2, FlushInterval: time.Hour})
+
+	require.NoError(t, s.Write(context.Background(), makeRecords(5)))
+	require.NoError(t, s.Close(context.Background()))
+
+	assert.Equal(t, 5, next.count())
+	for _, b := range next.batches {
+		assert.LessOrEqual(t, len(b), 2)
+	}
+	assert.Zero(t, s.Dropped())
+}
+
+func TestAsyncSink_FlushInterval(t *testing.T) {
+	next := &recordingSink{}
+	s := NewAsyncSink(next, AsyncOptions{BatchSize: 100, FlushInterval: 10 * time.Millisecond})
+	defer s.Close(context.Background())
+
+	require.NoError(t, s.Write(context.Background(), makeRecords(1)))
+	assert.Eventually(t, func() bool { return next.count() == 1 }, time.Second, 5*time.Millisecond)
+}
+
+func TestAsyncSink_DropsWhenFull(t *testing.T) {
+	next := &recordingSink{gate: make(chan struct{})}
+	s := NewAsyncSink(next, AsyncOptions{BufferSize: 2, BatchSize: 1, FlushInterval: time.Hour})
+
+	// the worker holds at most one record while blocked on the gate
+	require.NoError(t, s.Write(context.Background(), makeRecords(10)))
+	assert.GreaterOrEqual(t, s.Dropped(), uint64(7))
+
+	close(next.gate)
+	require.NoError(t, s.Close(context.Background()))
+	assert.Equal(t, uint64(10), s.Dropped()+uint64(next.count()))
+}
+
+func TestAsyncSink_BlockWhenFullHonorsContext(t *testing.T) {
+	next := &recordingSink{gate: make(chan struct{})}
+	s := NewAsyncSink(next, AsyncOptions{BufferSize: 1, BatchSize: 1, FlushInterval: time.Hour, BlockWhenFull: true})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := s.Write(ctx, makeRecords(5))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.NotZero(t, s.Dropped())
+
+	close(next.gate)
+	require.NoError(t, s.Close(context.Background()))
+}
+
+func TestAsyncSink_CloseReleasesBlockedWriters(t *testing.T) {
+	next := &recordingSink{gate: make(chan struct{})}
+	defer close(next.gate)
+	s := NewAsyncSink(next, AsyncOptions{BufferSize: 1, BatchSize: 1, FlushInterval: time.Hour, BlockWhenFull: true})
+
+	written := make(chan error, 1)
+	go func() { written <- s.Write(context.Background(), makeRecords(5)) }()
+	require.Eventually(t, func() bool { return len(s.queue) == cap(s.queue) }, time.Second, time.Millisecond)
+
+	closed := make(chan error, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		closed <- s.Close(ctx)
+	}()
+
+	select {
+	case err := <-closed:
+		assert.ErrorIs(t, err, context.DeadlineExceeded, "the next sink is still busy")
+	case <-time.After(time.Second):
+		t.Fatal("Close did not honor its context")
+	}
+	assert.ErrorIs(t, <-written, ErrSinkClosed)
+	assert.NotZero(t, s.Dropped())
+}
+
+func TestAsyncSink_CountsFailures(t *testing.T) {
+	next := &recordingSink{err: errors.New("disk full")}
+	s := NewAsyncSink(next, AsyncOptions{})
+
+	require.NoError(t, s.Write(context.Background(), makeRecords(3)))
+	require.NoError(t, s.Close(context.Background()))
+	assert.Equal(t, uint64(3), s.Failed())
+}
+
+func TestAsyncSink_WriteAfterClose(t *testing.T) {
+	s := NewAsyncSink(NewNopSink(), AsyncOptions{})
+	require.NoError(t, s.Close(context.Background()))
+	require.NoError(t, s.Close(context.Background()))
+
+	assert.ErrorIs(t, s.Write(context.Background(), makeRecords(1)), ErrSinkClosed)
+}
+
+func TestAsyncSink_CloseTimeout(t *testing.T) {
+	next := &recordingSink{gate: make(chan struct{})}
+	defer close(next.gate)
+	s := NewAsyncSink(next, AsyncOptions{BatchSize: 1})
+	require.NoError(t, s.Write(context.Background(), makeRecords(1)))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, s.Close(ctx), context.DeadlineExceeded)
+}
+
+func TestAsyncSink_Ping(t *testing.T) {
+	s := NewAsyncSink(NewCSVSink(filepath.Join(t.TempDir(), "missing", "errors.csv"), RotationOptions{}), AsyncOptions{})
+	assert.Error(t, s.Ping(context.Background()), "next sink is not writable")
+
+	s = NewAsyncSink(&recordingSink{}, AsyncOptions{})
+	assert.NoError(t, s.Ping(context.Background()))
+	require.NoError(t, s.Close(context.Background()))
+	assert.ErrorIs(t, s.Ping(context.Background()), ErrSinkClosed)
+}
@@ -0,0 +1,267 @@
+package validatorwrapper
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CSV headers by schema version. The header identifies the columns of a
+// file, so files written by older versions remain readable. Only append
+// new versions, csvHeader is the one written.
+var (
+	csvHeaderV1 = []string{"timestamp", "struct_and_field_name", "error_tag"}
+	csvHeaderV2 = []string{"timestamp", "struct_and_field_name", "error_tag",
+		"route", "method", "request_id", "remote_ip", "user_agent"}
+
+	csvHeader = csvHeaderV2
+)
+
+type csvSink struct {
+	mu      sync.Mutex
+	path    string
+	rotator rotator
+	// checked is set once the file at path is known to use csvHeader.
+	checked bool
+}
+
+// NewCSVSink appends records to the CSV file at path, writing a header
+// when the file is new. The file is rotated according to rotation before
+// a write when it has to be.
+func NewCSVSink(path string, rotation RotationOptions) *csvSink {
+	return &csvSink{
+		mu:   sync.Mutex{},
+		path: path,
+		rotator: rotator{
+			path: path,
+			opts: rotation,
+			now:  time.Now,
+		},
+	}
+}
+
+func (s *csvSink) Write(ctx context.Context, records []ErrorRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Rotated files get a fresh header in the new file
+	if err := s.rotator.rotateIfNeeded(); err != nil {
+		return fmt.Errorf("rotate CSV file: %w", err)
+	}
+
+	// Never append rows to a file written with an older header
+	if !s.checked {
+		if err := s.rotateOldHeader(); err != nil {
+			return err
+		}
+	}
+
+	// Check if file exists to determine if we need to write headers
+	fileExists := true
+	_, err := os.Stat(s.path)
+	if os.IsNotExist(err) {
+		fileExists = false
+	}
+
+	// Open CSV file in append mode
+	file, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("open CSV file: %w", err)
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	defer writer.Flush()
+
+	// Write header if file is new
+	if !fileExists {
+		if err := writer.Write(csvHeader); err != nil {
+			return fmt.Errorf("write CSV header: %w", err)
+		}
+	}
+	s.checked = true
+
+	// Collect all rows
+	rows := make([][]string, 0, len(records))
+	for _, r := range records {
+		row := []string{
+			r.Timestamp.Format(time.RFC3339),
+			r.StructNamespace,
+			r.Tag,
+			r.Route,
+			r.Method,
+			r.RequestID,
+			r.RemoteIP,
+			r.UserAgent,
+		}
+		rows = append(rows, row)
+	}
+
+	// Write all rows at once
+	if err := writer.WriteAll(rows); err != nil {
+		return fmt.Errorf("write CSV rows: %w", err)
+	}
+	return nil
+}
+
+// rotateOldHeader moves the file aside when its header is not csvHeader.
+func (s *csvSink) rotateOldHeader() error {
+	file, err := os.Open(s.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("open CSV file: %w", err)
+	}
+	header, err := csv.NewReader(file).Read()
+	file.Close()
+	if err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("read CSV header: %w", err)
+	}
+	if slices.Equal(header, csvHeader) {
+		return nil
+	}
+	if err := s.rotator.rotate(); err != nil {
+		return fmt.Errorf("rotate CSV file: %w", err)
+	}
+	return nil
+}
+
+// Ping checks that the CSV file can be appended to.
+func (s *csvSink) Ping(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := checkWritable(s.path); err != nil {
+		return fmt.Errorf("CSV file not writable: %w", err)
+	}
+	return nil
+}
+
+// Scan reads back the rotated files, oldest first, then the current one.
+// The lock is only held to open the files, records written during the scan
+// are not read.
+func (s *csvSink) Scan(ctx context.Context, fn func(ErrorRecord) error) error {
+	files, err := s.openFiles()
+	defer func() {
+		for _, f := range files {
+			f.file.Close()
+		}
+	}()
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		if err := scanCSVFile(f, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// scanFile is a file opened for a scan. Only its first size bytes are read,
+// so a scan stops at the last row written when it started.
+type scanFile struct {
+	path string
+	file *os.File
+	size int64
+}
+
+// openFiles opens the rotated files and the current one under the lock. They
+// stay readable when rotation renames or removes them afterwards.
+func (s *csvSink) openFiles() ([]scanFile, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	paths, err := s.rotator.backups()
+	if err != nil {
+		return nil, fmt.Errorf("list CSV backups: %w", err)
+	}
+	paths = append(paths, s.path)
+	files := make([]scanFile, 0, len(paths))
+	for _, path := range paths {
+		f, err := openScanFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return files, fmt.Errorf("open CSV file: %w", err)
+		}
+		files = append(files, f)
+	}
+	return files, nil
+}
+
+func openScanFile(path string) (scanFile, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return scanFile{}, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return scanFile{}, err
+	}
+	return scanFile{path: path, file: file, size: info.Size()}, nil
+}
+
+// scanCSVFile calls fn for every row of f, which may be gzipped.
+func scanCSVFile(f scanFile, fn func(ErrorRecord) error) error {
+	var r io.Reader = io.LimitReader(f.file, f.size)
+	if strings.HasSuffix(f.path, ".gz") {
+		zr, err := gzip.NewReader(r)
+		if err != nil {
+			return fmt.Errorf("read %s: %w", f.path, err)
+		}
+		defer zr.Close()
+		r = zr
+	}
+
+	// Rows must have as many fields as the header
+	reader := csv.NewReader(r)
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read %s: %w", f.path, err)
+	}
+	if !slices.Equal(header, csvHeaderV1) && !slices.Equal(header, csvHeaderV2) {
+		return fmt.Errorf("read %s: unknown CSV header %q", f.path, header)
+	}
+	for {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("read %s: %w", f.path, err)
+		}
+		ts, err := time.Parse(time.RFC3339, row[0])
+		if err != nil {
+			line, _ := reader.FieldPos(0)
+			return fmt.Errorf("read %s: line %d: %w", f.path, line, err)
+		}
+		record := ErrorRecord{Timestamp: ts, StructNamespace: row[1], Tag: row[2]}
+		if len(row) == len(csvHeaderV2) {
+			record.RequestMeta = RequestMeta{
+				Route:     row[3],
+				Method:    row[4],
+				RequestID: row[5],
+				RemoteIP:  row[6],
+				UserAgent: row[7],
+			}
+		}
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package validatorwrapper
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+type jsonLinesSink struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewJSONLinesSink appends one JSON object per record to the file at path.
+func NewJSONLinesSink(path string) *jsonLinesSink {
+	return &jsonLinesSink{
+		mu:   sync.Mutex{},
+		path: path,
+	}
+}
+
+func (s *jsonLinesSink) Write(ctx context.Context, records []ErrorRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("open JSON lines file: %w", err)
+	}
+	defer file.Close()
+
+	return writeJSONLines(file, records)
+}
+
+// Ping checks that the JSON lines file can be appended to.
+func (s *jsonLinesSink) Ping(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := checkWritable(s.path); err != nil {
+		return fmt.Errorf("JSON lines file not writable: %w", err)
+	}
+	return nil
+}
+
+// Scan reads back every record of the JSON lines file. The lock is only held
+// to open the file, records written during the scan are not read.
+func (s *jsonLinesSink) Scan(ctx context.Context, fn func(ErrorRecord) error) error {
+	s.mu.Lock()
+	f, err := openScanFile(s.path)
+	s.mu.Unlock()
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("open JSON lines file: %w", err)
+	}
+	defer f.file.Close()
+
+	dec := json.NewDecoder(bufio.NewReader(io.LimitReader(f.file, f.size)))
+	for {
+		var r ErrorRecord
+		err := dec.Decode(&r)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("read JSON lines file: %w", err)
+		}
+		if err := fn(r); err != nil {
+			return err
+		}
+	}
+}
+
+type stdoutSink struct {
+	mu  sync.Mutex
+	out io.Writer
+}
+
+// NewStdoutSink writes records to stdout as JSON lines, which suits
+// read-only containers where logs are collected from the process output.
+func NewStdoutSink() *stdoutSink {
+	return &stdoutSink{
+		mu:  sync.Mutex{},
+		out: os.Stdout,
+	}
+}
+
+func (s *stdoutSink) Write(ctx context.Context, records []ErrorRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return writeJSONLines(s.out, records)
+}
+
+func writeJSONLines(w io.Writer, records []ErrorRecord) error {
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	for _, r := range records {
+		if err := enc.Encode(r); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,158 @@
+package validatorwrapper
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+)
+
+// RecordScanner is implemented by sinks whose records can be read back.
+type RecordScanner interface {
+	// Scan calls fn for every stored record, oldest first, and stops at
+	// the first error fn returns.
+	Scan(ctx context.Context, fn func(ErrorRecord) error) error
+}
+
+// ErrorFilter selects records. Zero fields match everything.
+type ErrorFilter struct {
+	// From and To bound the record timestamps, From inclusive and To
+	// exclusive.
+	From time.Time
+	To   time.Time
+	// Namespace matches a field such as "FavoriteNumRequest.UserID", or
+	// every field of a struct such as "FavoriteNumRequest".
+	Namespace string
+	Tag       string
+}
+
+// Match reports whether r passes the filter.
+func (f ErrorFilter) Match(r ErrorRecord) bool {
+	if !f.inRange(r) {
+		return false
+	}
+	if f.Namespace != "" && r.StructNamespace != f.Namespace &&
+		!strings.HasPrefix(r.StructNamespace, f.Namespace+".") {
+		return false
+	}
+	if f.Tag != "" && r.Tag != f.Tag {
+		return false
+	}
+	return true
+}
+
+// inRange reports whether r is between From and To.
+func (f ErrorFilter) inRange(r ErrorRecord) bool {
+	if !f.From.IsZero() && r.Timestamp.Before(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && !r.Timestamp.Before(f.To) {
+		return false
+	}
+	return true
+}
+
+// FieldCount is the number of failures recorded for one field, with the
+// failing tags.
+type FieldCount struct {
+	StructNamespace string         `json:"structAndFieldName"`
+	Count           int            `json:"count"`
+	Tags            map[string]int `json:"tags"`
+}
+
+var (
+	ErrScanLimit = errors.New("too many records in the time range")
+)
+
+// ErrorQuerier answers queries over the records of a RecordScanner. Every
+// query reads the whole storage, so it suits admin use rather than the
+// request path.
+type ErrorQuerier struct {
+	src RecordScanner
+	// maxScan fails the queries with more records in their time range, 0
+	// never fails.
+	maxScan int
+}
+
+// NewErrorQuerier queries src, failing with ErrScanLimit once a query has
+// met more than maxScan records between its From and To. Narrowing the
+// time range lets such a query through. 0 never fails.
+func NewErrorQuerier(src RecordScanner, maxScan int) *ErrorQuerier {
+	return &ErrorQuerier{src: src, maxScan: maxScan}
+}
+
+// scan calls fn for every record of src in the time range of f, up to
+// maxScan of them.
+func (q *ErrorQuerier) scan(ctx context.Context, f ErrorFilter, fn func(ErrorRecord)) error {
+	scanned := 0
+	return q.src.Scan(ctx, func(r ErrorRecord) error {
+		if !f.inRange(r) {
+			return ctx.Err()
+		}
+		scanned++
+		if q.maxScan > 0 && scanned > q.maxScan {
+			return ErrScanLimit
+		}
+		fn(r)
+		return ctx.Err()
+	})
+}
+
+// List returns up to limit records matching f, oldest first, after
+// skipping offset of them, along with the number of matching records.
+func (q *ErrorQuerier) List(ctx context.Context, f ErrorFilter, offset, limit int) ([]ErrorRecord, int, error) {
+	records := []ErrorRecord{}
+	total := 0
+	err := q.scan(ctx, f, func(r ErrorRecord) {
+		if !f.Match(r) {
+			return
+		}
+		if total >= offset && len(records) < limit {
+			records = append(records, r)
+		}
+		total++
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return records, total, nil
+}
+
+// Top returns the n fields failing most often among the records matching
+// f, along with the number of matching records.
+func (q *ErrorQuerier) Top(ctx context.Context, f ErrorFilter, n int) ([]FieldCount, int, error) {
+	counts := map[string]*FieldCount{}
+	total := 0
+	err := q.scan(ctx, f, func(r ErrorRecord) {
+		if !f.Match(r) {
+			return
+		}
+		fc, ok := counts[r.StructNamespace]
+		if !ok {
+			fc = &FieldCount{StructNamespace: r.StructNamespace, Tags: map[string]int{}}
+			counts[r.StructNamespace] = fc
+		}
+		fc.Count++
+		fc.Tags[r.Tag]++
+		total++
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	top := make([]FieldCount, 0, len(counts))
+	for _, fc := range counts {
+		top = append(top, *fc)
+	}
+	sort.Slice(top, func(i, j int) bool {
+		if top[i].Count != top[j].Count {
+			return top[i].Count > top[j].Count
+		}
+		return top[i].StructNamespace < top[j].StructNamespace
+	})
+	if len(top) > n {
+		top = top[:n]
+	}
+	return top, total, nil
+}
@@ -0,0 +1,192 @@
+package validatorwrapper
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sliceScanner serves records from memory.
+type sliceScanner []ErrorRecord
+
+func (s sliceScanner) Scan(ctx context.Context, fn func(ErrorRecord) error) error {
+	for _, r := range s {
+		if err := fn(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var day = time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+
+var queryRecords = sliceScanner{
+	{Timestamp: day, StructNamespace: "FavoriteNumRequest.UserID", Tag: "uuid_rfc4122"},
+	{Timestamp: day.Add(time.Hour), StructNamespace: "FavoriteNumRequest.FavNum", Tag: "gt"},
+	{Timestamp: day.Add(2 * time.Hour), StructNamespace: "FavoriteNumRequest.UserID", Tag: "required"},
+	{Timestamp: day.Add(3 * time.Hour), StructNamespace: "PetNameRequest.PetName", Tag: "min"},
+	{Timestamp: day.Add(4 * time.Hour), StructNamespace: "FavoriteNumRequest.UserID", Tag: "uuid_rfc4122"},
+}
+
+func TestErrorFilter_Match(t *testing.T) {
+	r := queryRecords[0]
+	tests := []struct {
+		name   string
+		filter ErrorFilter
+		want   bool
+	}{
+		{name: "empty", filter: ErrorFilter{}, want: true},
+		{name: "from inclusive", filter: ErrorFilter{From: day}, want: true},
+		{name: "before from", filter: ErrorFilter{From: day.Add(time.Second)}, want: false},
+		{name: "to exclusive", filter: ErrorFilter{To: day}, want: false},
+		{name: "field", filter: ErrorFilter{Namespace: "FavoriteNumRequest.UserID"}, want: true},
+		{name: "struct", filter: ErrorFilter{Namespace: "FavoriteNumRequest"}, want: true},
+		{name: "name prefix only", filter: ErrorFilter{Namespace: "FavoriteNum"}, want: false},
+		{name: "tag", filter: ErrorFilter{Tag: "uuid_rfc4122"}, want: true},
+		{name: "other tag", filter: ErrorFilter{Tag: "required"}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.filter.Match(r))
+		})
+	}
+}
+
+func TestErrorQuerier_List(t *testing.T) {
+	q := NewErrorQuerier(queryRecords, 0)
+
+	records, total, err := q.List(context.Background(), ErrorFilter{Namespace: "FavoriteNumRequest"}, 1, 2)
+
+	require.NoError(t, err)
+	assert.Equal(t, 4, total)
+	assert.Equal(t, []ErrorRecord{queryRecords[1], queryRecords[2]}, records)
+}
+
+func TestErrorQuerier_List_PastTheEnd(t *testing.T) {
+	q := NewErrorQuerier(queryRecords, 0)
+
+	records, total, err := q.List(context.Background(), ErrorFilter{}, 10, 5)
+
+	require.NoError(t, err)
+	assert.Equal(t, 5, total)
+	assert.Empty(t, records)
+}
+
+func TestErrorQuerier_Top(t *testing.T) {
+	q := NewErrorQuerier(queryRecords, 0)
+
+	top, total, err := q.Top(context.Background(), ErrorFilter{To: day.Add(4 * time.Hour)}, 2)
+
+	require.NoError(t, err)
+	assert.Equal(t, 4, total)
+	assert.Equal(t, []FieldCount{
+		{StructNamespace: "FavoriteNumRequest.UserID", Count: 2, Tags: map[string]int{"uuid_rfc4122": 1, "required": 1}},
+		{StructNamespace: "FavoriteNumRequest.FavNum", Count: 1, Tags: map[string]int{"gt": 1}},
+	}, top)
+}
+
+func TestErrorQuerier_ScanError(t *testing.T) {
+	scanErr := errors.New("disk read error")
+	q := NewErrorQuerier(scannerFunc(func(ctx context.Context, fn func(ErrorRecord) error) error {
+		return scanErr
+	}), 0)
+
+	_, _, err := q.List(context.Background(), ErrorFilter{}, 0, 10)
+	assert.ErrorIs(t, err, scanErr)
+	_, _, err = q.Top(context.Background(), ErrorFilter{}, 10)
+	assert.ErrorIs(t, err, scanErr)
+}
+
+func TestErrorQuerier_ScanLimit(t *testing.T) {
+	q := NewErrorQuerier(queryRecords, len(queryRecords)-1)
+
+	_, _, err := q.List(context.Background(), ErrorFilter{Tag: "no match"}, 0, 10)
+	assert.ErrorIs(t, err, ErrScanLimit)
+	_, _, err = q.Top(context.Background(), ErrorFilter{}, 10)
+	assert.ErrorIs(t, err, ErrScanLimit)
+
+	_, total, err := NewErrorQuerier(queryRecords, len(queryRecords)).List(context.Background(), ErrorFilter{}, 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, len(queryRecords), total)
+
+	// records out of the time range do not count
+	_, total, err = q.List(context.Background(), ErrorFilter{From: day.Add(time.Hour)}, 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, len(queryRecords)-1, total)
+}
+
+type scannerFunc func(ctx context.Context, fn func(ErrorRecord) error) error
+
+func (f scannerFunc) Scan(ctx context.Context, fn func(ErrorRecord) error) error {
+	return f(ctx, fn)
+}
+
+func collect(t *testing.T, s RecordScanner) []ErrorRecord {
+	t.Helper()
+	var got []ErrorRecord
+	require.NoError(t, s.Scan(context.Background(), func(r ErrorRecord) error {
+		got = append(got, r)
+		return nil
+	}))
+	return got
+}
+
+func TestCSVSink_Scan(t *testing.T) {
+	s, clock, _ := newRotatingCSVSink(t, RotationOptions{MaxSize: 1, Compress: true})
+	assert.Empty(t, collect(t, s))
+
+	for _, r := range queryRecords[:3] {
+		require.NoError(t, s.Write(context.Background(), []ErrorRecord{r}))
+		clock.t = clock.t.Add(time.Second)
+	}
+
+	// two gzipped backups then the current file, in write order
+	assert.Equal(t, []ErrorRecord(queryRecords[:3]), collect(t, s))
+}
+
+func TestJSONLinesSink_Scan(t *testing.T) {
+	s := NewJSONLinesSink(filepath.Join(t.TempDir(), "errors.jsonl"))
+	assert.Empty(t, collect(t, s))
+
+	require.NoError(t, s.Write(context.Background(), queryRecords))
+
+	assert.Equal(t, []ErrorRecord(queryRecords), collect(t, s))
+}
+
+func TestCSVSink_ScanDoesNotBlockWrites(t *testing.T) {
+	s, clock, _ := newRotatingCSVSink(t, RotationOptions{MaxSize: 1, Compress: true})
+	require.NoError(t, s.Write(context.Background(), queryRecords[:1]))
+
+	// the write rotates the file being read, it would deadlock if the scan
+	// held the lock
+	var got []ErrorRecord
+	err := s.Scan(context.Background(), func(r ErrorRecord) error {
+		got = append(got, r)
+		clock.t = clock.t.Add(time.Second)
+		return s.Write(context.Background(), queryRecords[1:2])
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []ErrorRecord(queryRecords[:1]), got)
+	assert.Equal(t, []ErrorRecord(queryRecords[:2]), collect(t, s))
+}
+
+func TestJSONLinesSink_ScanDoesNotBlockWrites(t *testing.T) {
+	s := NewJSONLinesSink(filepath.Join(t.TempDir(), "errors.jsonl"))
+	require.NoError(t, s.Write(context.Background(), queryRecords[:1]))
+
+	var got []ErrorRecord
+	err := s.Scan(context.Background(), func(r ErrorRecord) error {
+		got = append(got, r)
+		return s.Write(context.Background(), queryRecords[1:2])
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []ErrorRecord(queryRecords[:1]), got)
+	assert.Equal(t, []ErrorRecord(queryRecords[:2]), collect(t, s))
+}
@@ -0,0 +1,177 @@
+package validatorwrapper
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// RotationOptions bounds the disk used by a file sink. The zero value never
+// rotates.
+type RotationOptions struct {
+	// MaxSize rotates the file once it reaches this many bytes.
+	MaxSize int64
+	// Daily rotates the file on the first write of a new day.
+	Daily bool
+	// MaxBackups is the number of rotated files to keep, 0 keeps them all.
+	MaxBackups int
+	// Compress gzips the rotated files.
+	Compress bool
+}
+
+// backupTimeLayout names rotated files, it sorts in chronological order.
+const backupTimeLayout = "20060102T150405.000000000"
+
+// rotator moves the file at path aside when it has to be rotated. It is not
+// safe for concurrent use, callers hold their own lock.
+type rotator struct {
+	path string
+	opts RotationOptions
+	now  func() time.Time
+}
+
+// rotateIfNeeded rotates the file when it is too big or was last written on
+// a previous day, then prunes the oldest backups.
+func (r *rotator) rotateIfNeeded() error {
+	info, err := os.Stat(r.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !r.shouldRotate(info) {
+		return nil
+	}
+	return r.rotate()
+}
+
+// rotate moves the file aside, compressing it when asked to, then prunes
+// the oldest backups.
+func (r *rotator) rotate() error {
+	backup, err := r.backupName()
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(r.path, backup); err != nil {
+		return err
+	}
+	if r.opts.Compress {
+		if err := gzipFile(backup); err != nil {
+			return fmt.Errorf("compress %s: %w", backup, err)
+		}
+	}
+	return r.prune()
+}
+
+func (r *rotator) shouldRotate(info os.FileInfo) bool {
+	if r.opts.MaxSize > 0 && info.Size() >= r.opts.MaxSize {
+		return true
+	}
+	if r.opts.Daily {
+		y1, m1, d1 := info.ModTime().Date()
+		y2, m2, d2 := r.now().Date()
+		return y1 != y2 || m1 != m2 || d1 != d2
+	}
+	return false
+}
+
+// backupName returns "<name>-<time><ext>", e.g. errors-20250102T030405.000000000.csv.
+func (r *rotator) backupName() (string, error) {
+	ext := filepath.Ext(r.path)
+	base := strings.TrimSuffix(r.path, ext)
+	t := r.now().UTC()
+	for {
+		name := base + "-" + t.Format(backupTimeLayout) + ext
+		_, err := os.Stat(name)
+		if os.IsNotExist(err) {
+			_, err = os.Stat(name + ".gz")
+		}
+		if os.IsNotExist(err) {
+			return name, nil
+		}
+		if err != nil {
+			return "", err
+		}
+		t = t.Add(time.Nanosecond)
+	}
+}
+
+// backups returns the rotated files of path, oldest first.
+func (r *rotator) backups() ([]string, error) {
+	ext := filepath.Ext(r.path)
+	prefix := strings.TrimSuffix(filepath.Base(r.path), ext) + "-"
+
+	entries, err := os.ReadDir(filepath.Dir(r.path))
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, e := range entries {
+		name := e.Name()
+		stamp, ok := strings.CutPrefix(name, prefix)
+		if !ok || e.IsDir() {
+			continue
+		}
+		stamp = strings.TrimSuffix(strings.TrimSuffix(stamp, ".gz"), ext)
+		if _, err := time.Parse(backupTimeLayout, stamp); err != nil {
+			continue
+		}
+		names = append(names, filepath.Join(filepath.Dir(r.path), name))
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (r *rotator) prune() error {
+	if r.opts.MaxBackups <= 0 {
+		return nil
+	}
+	names, err := r.backups()
+	if err != nil {
+		return err
+	}
+	for len(names) > r.opts.MaxBackups {
+		if err := os.Remove(names[0]); err != nil {
+			return err
+		}
+		names = names[1:]
+	}
+	return nil
+}
+
+// gzipFile replaces path by path.gz.
+func gzipFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(dst)
+	zw.Name = filepath.Base(path)
+	if _, err := io.Copy(zw, src); err != nil {
+		dst.Close()
+		os.Remove(dst.Name())
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		dst.Close()
+		os.Remove(dst.Name())
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		os.Remove(dst.Name())
+		return err
+	}
+	return os.Remove(path)
+}
@@ -0,0 +1,140 @@
+package validatorwrapper
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock lets tests move the time seen by the rotator.
+type fakeClock struct {
+	t time.Time
+}
+
+func (c *fakeClock) now() time.Time {
+	return c.t
+}
+
+func newRotatingCSVSink(t *testing.T, opts RotationOptions) (*csvSink, *fakeClock, string) {
+	t.Helper()
+	dir := t.TempDir()
+	clock := &fakeClock{t: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)}
+	s := NewCSVSink(filepath.Join(dir, "errors.csv"), opts)
+	s.rotator.now = clock.now
+	return s, clock, dir
+}
+
+func dirNames(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	return names
+}
+
+func TestCSVSink_RotatesBySize(t *testing.T) {
+	s, clock, dir := newRotatingCSVSink(t, RotationOptions{MaxSize: 200})
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, s.Write(context.Background(), testRecords))
+		require.NoError(t, s.Write(context.Background(), testRecords))
+		clock.t = clock.t.Add(time.Second)
+	}
+
+	// the header and one row stay below 200 bytes, a second row crosses it
+	assert.Equal(t, []string{
+		"errors-20250102T030406.000000000.csv",
+		"errors-20250102T030407.000000000.csv",
+		"errors.csv",
+	}, dirNames(t, dir))
+
+	for _, name := range dirNames(t, dir) {
+		b, err := os.ReadFile(filepath.Join(dir, name))
+		require.NoError(t, err)
+		lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+		assert.Equal(t, strings.Join(csvHeader, ","), lines[0], name)
+		assert.Len(t, lines, 3, name)
+	}
+}
+
+func TestCSVSink_RotatesDaily(t *testing.T) {
+	s, clock, dir := newRotatingCSVSink(t, RotationOptions{Daily: true})
+	path := filepath.Join(dir, "errors.csv")
+
+	require.NoError(t, s.Write(context.Background(), testRecords))
+	require.NoError(t, os.Chtimes(path, clock.t, clock.t))
+
+	// same day: keep appending
+	clock.t = clock.t.Add(time.Hour)
+	require.NoError(t, s.Write(context.Background(), testRecords))
+	require.NoError(t, os.Chtimes(path, clock.t, clock.t))
+	assert.Equal(t, []string{"errors.csv"}, dirNames(t, dir))
+
+	// next day: rotate before writing
+	clock.t = clock.t.Add(24 * time.Hour)
+	require.NoError(t, s.Write(context.Background(), testRecords))
+	assert.Len(t, dirNames(t, dir), 2)
+}
+
+func TestCSVSink_KeepsMaxBackupsCompressed(t *testing.T) {
+	s, clock, dir := newRotatingCSVSink(t, RotationOptions{MaxSize: 1, MaxBackups: 2, Compress: true})
+
+	for i := 0; i < 5; i++ {
+		require.NoError(t, s.Write(context.Background(), testRecords))
+		clock.t = clock.t.Add(time.Minute)
+	}
+
+	// the two newest backups are kept, the oldest ones were pruned
+	assert.Equal(t, []string{
+		"errors-20250102T030705.000000000.csv.gz",
+		"errors-20250102T030805.000000000.csv.gz",
+		"errors.csv",
+	}, dirNames(t, dir))
+
+	f, err := os.Open(filepath.Join(dir, "errors-20250102T030805.000000000.csv.gz"))
+	require.NoError(t, err)
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	require.NoError(t, err)
+	b, err := io.ReadAll(zr)
+	require.NoError(t, err)
+	assert.Equal(t, "timestamp,struct_and_field_name,error_tag,route,method,request_id,remote_ip,user_agent\n2025-01-02T03:04:05Z,FavoriteNumRequest.UserID,uuid_rfc4122,,,,,\n", string(b))
+}
+
+func TestCSVSink_RotationIgnoresOtherFiles(t *testing.T) {
+	s, clock, dir := newRotatingCSVSink(t, RotationOptions{MaxSize: 1, MaxBackups: 1})
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "errors-notes.csv"), nil, 0644))
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, s.Write(context.Background(), testRecords))
+		clock.t = clock.t.Add(time.Second)
+	}
+
+	assert.Equal(t, []string{
+		"errors-20250102T030407.000000000.csv",
+		"errors-notes.csv",
+		"errors.csv",
+	}, dirNames(t, dir))
+}
+
+func TestCSVSink_RotationSameInstant(t *testing.T) {
+	s, _, dir := newRotatingCSVSink(t, RotationOptions{MaxSize: 1})
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, s.Write(context.Background(), testRecords))
+	}
+
+	// backups never overwrite each other even when the clock does not move
+	assert.Len(t, dirNames(t, dir), 3)
+}
@@ -0,0 +1,151 @@
+package validatorwrapper
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/BoomNooB/medium-go-di/redact"
+	"github.com/BoomNooB/medium-go-di/requestid"
+	"github.com/go-playground/validator/v10"
+)
+
+// ErrorRecord is a single failing field as stored by an ErrorSink.
+// Value is already redacted when the field carries a pii tag.
+type ErrorRecord struct {
+	Timestamp       time.Time `json:"timestamp"`
+	StructNamespace string    `json:"structAndFieldName"`
+	Tag             string    `json:"errorTag"`
+	Value           any       `json:"value,omitempty"`
+	// RequestMeta is the request that failed, when the caller set it on
+	// the context given to StructValidation. RequestID defaults to the
+	// one of requestid.FromContext.
+	RequestMeta
+}
+
+// RequestMeta describes the HTTP request being validated, so a record can
+// be correlated with it.
+type RequestMeta struct {
+	Route     string `json:"route,omitempty"`
+	Method    string `json:"method,omitempty"`
+	RequestID string `json:"requestId,omitempty"`
+	RemoteIP  string `json:"remoteIp,omitempty"`
+	UserAgent string `json:"userAgent,omitempty"`
+}
+
+type requestMetaKey struct{}
+
+// ContextWithRequestMeta attaches meta to the records written for the
+// validation failures of ctx.
+func ContextWithRequestMeta(ctx context.Context, meta RequestMeta) context.Context {
+	return context.WithValue(ctx, requestMetaKey{}, meta)
+}
+
+func requestMetaFromContext(ctx context.Context) RequestMeta {
+	meta, _ := ctx.Value(requestMetaKey{}).(RequestMeta)
+	return meta
+}
+
+// ErrorSink receives the validation errors of one failed request.
+// Implementations must be safe for concurrent use.
+type ErrorSink interface {
+	Write(ctx context.Context, records []ErrorRecord) error
+}
+
+// Pinger is implemented by sinks that can check they are able to accept
+// records. Sinks without it are always considered ready.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// PingSink checks sink when it implements Pinger.
+func PingSink(ctx context.Context, sink ErrorSink) error {
+	if p, ok := sink.(Pinger); ok {
+		return p.Ping(ctx)
+	}
+	return nil
+}
+
+const (
+	SinkCSV       = "csv"
+	SinkJSONLines = "jsonl"
+	SinkStdout    = "stdout"
+	SinkNop       = "none"
+)
+
+// NewErrorSink builds the sink named by kind. path is only used by the
+// file based sinks and rotation only by the CSV sink.
+func NewErrorSink(kind, path string, rotation RotationOptions) (ErrorSink, error) {
+	switch kind {
+	case SinkCSV:
+		return NewCSVSink(path, rotation), nil
+	case SinkJSONLines:
+		return NewJSONLinesSink(path), nil
+	case SinkStdout:
+		return NewStdoutSink(), nil
+	case SinkNop:
+		return NewNopSink(), nil
+	default:
+		return nil, fmt.Errorf("unknown error sink %q", kind)
+	}
+}
+
+// newErrorRecords converts the errors found on req, redacting the value of
+// every field tagged with pii.
+func newErrorRecords(ctx context.Context, req any, vErr validator.ValidationErrors) []ErrorRecord {
+	now := time.Now()
+	meta := requestMetaFromContext(ctx)
+	if meta.RequestID == "" {
+		meta.RequestID = requestid.FromContext(ctx)
+	}
+	reqType := reflect.TypeOf(req)
+	records := make([]ErrorRecord, 0, len(vErr))
+	for _, fieldErr := range vErr {
+		// StructNamespace starts with the struct name itself
+		path := strings.Split(fieldErr.StructNamespace(), ".")[1:]
+		tag := redact.FieldTag(reqType, path)
+		records = append(records, ErrorRecord{
+			Timestamp:       now,
+			StructNamespace: fieldErr.StructNamespace(),
+			Tag:             fieldErr.Tag(),
+			Value:           redact.Apply(tag, fieldErr.Value()),
+			RequestMeta:     meta,
+		})
+	}
+	return records
+}
+
+// checkWritable reports whether records can be appended to the file at
+// path without creating it, so a file sink still writes its header later.
+func checkWritable(path string) error {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err == nil {
+		return file.Close()
+	}
+	if !os.IsNotExist(err) {
+		return err
+	}
+
+	// The file is created on first write, probe its directory instead
+	probe, err := os.CreateTemp(filepath.Dir(path), ".probe-*")
+	if err != nil {
+		return err
+	}
+	probe.Close()
+	return os.Remove(probe.Name())
+}
+
+type nopSink struct{}
+
+// NewNopSink returns a sink that discards every record.
+func NewNopSink() *nopSink {
+	return &nopSink{}
+}
+
+func (nopSink) Write(ctx context.Context, records []ErrorRecord) error {
+	return nil
+}
@@ -0,0 +1,151 @@
+package validatorwrapper
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testRecords = []ErrorRecord{
+	{
+		Timestamp:       time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		StructNamespace: "FavoriteNumRequest.UserID",
+		Tag:             "uuid_rfc4122",
+	},
+}
+
+var testMeta = RequestMeta{
+	Route:     "/api/v1/favorite",
+	Method:    "POST",
+	RequestID: "req-1",
+	RemoteIP:  "192.0.2.1",
+	UserAgent: "curl/8.5.0",
+}
+
+func TestCSVSink_Write(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "errors.csv")
+	s := NewCSVSink(path, RotationOptions{})
+	withMeta := []ErrorRecord{testRecords[0]}
+	withMeta[0].RequestMeta = testMeta
+
+	require.NoError(t, s.Write(context.Background(), testRecords))
+	require.NoError(t, s.Write(context.Background(), withMeta))
+
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	assert.Equal(t, []string{
+		"timestamp,struct_and_field_name,error_tag,route,method,request_id,remote_ip,user_agent",
+		"2025-01-02T03:04:05Z,FavoriteNumRequest.UserID,uuid_rfc4122,,,,,",
+		"2025-01-02T03:04:05Z,FavoriteNumRequest.UserID,uuid_rfc4122,/api/v1/favorite,POST,req-1,192.0.2.1,curl/8.5.0",
+	}, lines)
+}
+
+func TestCSVSink_Write_RotatesOldHeader(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "errors.csv")
+	v1 := "timestamp,struct_and_field_name,error_tag\n2025-01-01T00:00:00Z,PetNameRequest.PetName,min\n"
+	require.NoError(t, os.WriteFile(path, []byte(v1), 0644))
+	s := NewCSVSink(path, RotationOptions{})
+	withMeta := []ErrorRecord{testRecords[0]}
+	withMeta[0].RequestMeta = testMeta
+
+	require.NoError(t, s.Write(context.Background(), withMeta))
+
+	// the old file is kept aside untouched and both stay readable
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	b, err := os.ReadFile(filepath.Join(dir, entries[0].Name()))
+	require.NoError(t, err)
+	assert.Equal(t, v1, string(b))
+
+	var got []ErrorRecord
+	require.NoError(t, s.Scan(context.Background(), func(r ErrorRecord) error {
+		got = append(got, r)
+		return nil
+	}))
+	assert.Equal(t, []ErrorRecord{
+		{Timestamp: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), StructNamespace: "PetNameRequest.PetName", Tag: "min"},
+		withMeta[0],
+	}, got)
+}
+
+func TestCSVSink_Scan_UnknownHeader(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "errors.csv")
+	require.NoError(t, os.WriteFile(path, []byte("when,what\n"), 0644))
+	s := NewCSVSink(path, RotationOptions{})
+
+	err := s.Scan(context.Background(), func(r ErrorRecord) error { return nil })
+
+	assert.ErrorContains(t, err, "unknown CSV header")
+}
+
+func TestJSONLinesSink_Write(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "errors.jsonl")
+	s := NewJSONLinesSink(path)
+	withMeta := []ErrorRecord{testRecords[0]}
+	withMeta[0].RequestMeta = testMeta
+
+	require.NoError(t, s.Write(context.Background(), withMeta))
+
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"timestamp": "2025-01-02T03:04:05Z",
+		"structAndFieldName": "FavoriteNumRequest.UserID",
+		"errorTag": "uuid_rfc4122",
+		"route": "/api/v1/favorite",
+		"method": "POST",
+		"requestId": "req-1",
+		"remoteIp": "192.0.2.1",
+		"userAgent": "curl/8.5.0"
+	}`, string(b))
+}
+
+func TestCSVSink_Write_Unwritable(t *testing.T) {
+	s := NewCSVSink(filepath.Join(t.TempDir(), "missing", "errors.csv"), RotationOptions{})
+	assert.Error(t, s.Write(context.Background(), testRecords))
+}
+
+func TestNewErrorSink(t *testing.T) {
+	for _, kind := range []string{SinkCSV, SinkJSONLines, SinkStdout, SinkNop} {
+		s, err := NewErrorSink(kind, filepath.Join(t.TempDir(), "errors"), RotationOptions{})
+		assert.NoError(t, err, kind)
+		assert.NotNil(t, s, kind)
+	}
+
+	_, err := NewErrorSink("kafka", "", RotationOptions{})
+	assert.Error(t, err)
+}
+
+func TestFileSinks_Ping(t *testing.T) {
+	dir := t.TempDir()
+	for _, s := range []Pinger{
+		NewCSVSink(filepath.Join(dir, "errors.csv"), RotationOptions{}),
+		NewJSONLinesSink(filepath.Join(dir, "errors.jsonl")),
+	} {
+		assert.NoError(t, s.Ping(context.Background()))
+	}
+
+	// Ping must not create the file, the CSV header is written on first write
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestFileSinks_Ping_Unwritable(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing")
+	for _, s := range []Pinger{
+		NewCSVSink(filepath.Join(missing, "errors.csv"), RotationOptions{}),
+		NewJSONLinesSink(filepath.Join(missing, "errors.jsonl")),
+	} {
+		assert.Error(t, s.Ping(context.Background()))
+	}
+}
@@ -0,0 +1,34 @@
+package validatorwrapper
+
+import "github.com/go-playground/validator/v10"
+
+const thaiCIDTag = "thai_cid"
+
+// validateThaiCID implements the official Thai citizen ID checksum.
+// The first 12 digits are weighted 13 down to 2, and the 13th digit
+// must equal (11 - sum mod 11) mod 10. IDs never start with 0 or 9.
+func validateThaiCID(fl validator.FieldLevel) bool {
+	return isValidThaiCID(fl.Field().String())
+}
+
+func isValidThaiCID(cid string) bool {
+	if len(cid) != 13 {
+		return false
+	}
+	if cid[0] == '0' || cid[0] == '9' {
+		return false
+	}
+
+	sum := 0
+	for i := 0; i < 13; i++ {
+		if cid[i] < '0' || cid[i] > '9' {
+			return false
+		}
+		if i < 12 {
+			sum += int(cid[i]-'0') * (13 - i)
+		}
+	}
+
+	check := (11 - sum%11) % 10
+	return int(cid[12]-'0') == check
+}
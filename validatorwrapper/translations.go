@@ -0,0 +1,103 @@
+package validatorwrapper
+
+import (
+	"context"
+
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/th"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	enTranslations "github.com/go-playground/validator/v10/translations/en"
+	thTranslations "github.com/go-playground/validator/v10/translations/th"
+	"golang.org/x/text/language"
+)
+
+const (
+	LocaleEnglish = "en"
+	LocaleThai    = "th"
+)
+
+// supportedLocales is ordered like localeMatcher, English first as the
+// default.
+var supportedLocales = []string{LocaleEnglish, LocaleThai}
+
+var localeMatcher = language.NewMatcher([]language.Tag{language.English, language.Thai})
+
+// customTranslations covers the tags the validator translations lack, by
+// locale then tag.
+var customTranslations = map[string]map[string]string{
+	LocaleEnglish: {
+		"uuid_rfc4122": "{0} must be a valid UUID",
+		thaiCIDTag:     "{0} must be a valid Thai citizen ID",
+	},
+	LocaleThai: {
+		"uuid_rfc4122": "{0} ต้องเป็น UUID ที่ถูกต้อง",
+		thaiCIDTag:     "{0} ต้องเป็นเลขประจำตัวประชาชนที่ถูกต้อง",
+	},
+}
+
+// MatchLocale picks the supported locale that best fits an Accept-Language
+// header value, falling back to English.
+func MatchLocale(acceptLanguage string) string {
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return LocaleEnglish
+	}
+	_, idx, confidence := localeMatcher.Match(tags...)
+	if confidence == language.No {
+		return LocaleEnglish
+	}
+	return supportedLocales[idx]
+}
+
+type localeKey struct{}
+
+// ContextWithLocale sets the locale of the messages returned by
+// StructValidation.
+func ContextWithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+func localeFromContext(ctx context.Context) string {
+	if locale, ok := ctx.Value(localeKey{}).(string); ok {
+		return locale
+	}
+	return LocaleEnglish
+}
+
+// newTranslator registers the English and Thai messages of every tag used by
+// the handler requests on v.
+func newTranslator(v *validator.Validate) (*ut.UniversalTranslator, error) {
+	uni := ut.New(en.New(), en.New(), th.New())
+
+	enTrans, _ := uni.GetTranslator(LocaleEnglish)
+	if err := enTranslations.RegisterDefaultTranslations(v, enTrans); err != nil {
+		return nil, err
+	}
+	thTrans, _ := uni.GetTranslator(LocaleThai)
+	if err := thTranslations.RegisterDefaultTranslations(v, thTrans); err != nil {
+		return nil, err
+	}
+
+	for locale, messages := range customTranslations {
+		trans, _ := uni.GetTranslator(locale)
+		for tag, message := range messages {
+			err := v.RegisterTranslation(tag, trans,
+				func(ut ut.Translator) error {
+					return ut.Add(tag, message, true)
+				},
+				func(ut ut.Translator, fe validator.FieldError) string {
+					msg, err := ut.T(fe.Tag(), fe.Field())
+					if err != nil {
+						return fe.Error()
+					}
+					return msg
+				},
+			)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return uni, nil
+}
@@ -0,0 +1,77 @@
+package validatorwrapper
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchLocale(t *testing.T) {
+	tests := []struct {
+		acceptLanguage string
+		want           string
+	}{
+		{acceptLanguage: "", want: LocaleEnglish},
+		{acceptLanguage: "th", want: LocaleThai},
+		{acceptLanguage: "th-TH,th;q=0.9,en;q=0.8", want: LocaleThai},
+		{acceptLanguage: "en-US,en;q=0.9,th;q=0.8", want: LocaleEnglish},
+		{acceptLanguage: "fr-FR,th;q=0.5", want: LocaleThai},
+		{acceptLanguage: "ja", want: LocaleEnglish},
+		{acceptLanguage: "not a header;;", want: LocaleEnglish},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, MatchLocale(tt.acceptLanguage), tt.acceptLanguage)
+	}
+}
+
+type translatedRequest struct {
+	ID        string `json:"id" validate:"required,uuid_rfc4122"`
+	CitizenID string `json:"citizenId" validate:"thai_cid"`
+	Count     int    `json:"count" validate:"gt=0"`
+}
+
+func TestStructValidation_Messages(t *testing.T) {
+	vw := NewValidatorWrapper(validator.New(validator.WithRequiredStructEnabled()), NewNopSink(), slog.New(slog.DiscardHandler))
+	req := &translatedRequest{ID: "not-a-uuid", CitizenID: "1234567890123"}
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want []string
+	}{
+		{
+			name: "default is English",
+			ctx:  context.Background(),
+			want: []string{
+				"id must be a valid UUID",
+				"citizenId must be a valid Thai citizen ID",
+				"count must be greater than 0",
+			},
+		},
+		{
+			name: "Thai",
+			ctx:  ContextWithLocale(context.Background(), LocaleThai),
+			want: []string{
+				"id ต้องเป็น UUID ที่ถูกต้อง",
+				"citizenId ต้องเป็นเลขประจำตัวประชาชนที่ถูกต้อง",
+				"count ต้องมีค่ามากกว่า 0",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var vErr *ValidationError
+			require.ErrorAs(t, vw.StructValidation(tt.ctx, req), &vErr)
+
+			got := make([]string, 0, len(vErr.Fields))
+			for _, f := range vErr.Fields {
+				got = append(got, f.Message)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -2,98 +2,182 @@ package validatorwrapper
 
 import (
 	"context"
-	"encoding/csv"
 	"errors"
 	"fmt"
-	"os"
-	"sync"
-	"time"
+	"log/slog"
+	"strings"
+	"sync/atomic"
 
+	"github.com/BoomNooB/medium-go-di/jsontag"
+	ut "github.com/go-playground/universal-translator"
 	"github.com/go-playground/validator/v10"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
 )
 
+const instrumentationName = "github.com/BoomNooB/medium-go-di/validatorwrapper"
+
 var (
 	ErrValidationFailed = errors.New("validation failed")
 )
 
-type validatorWrapper struct {
-	validator *validator.Validate
-	mu        sync.Mutex
-	csvFile   string
+// FieldError describes a single failing field of a validated request.
+type FieldError struct {
+	Field string `json:"field"`
+	Tag   string `json:"tag"`
+	Param string `json:"param,omitempty"`
+	Value any    `json:"value,omitempty"`
+	// Message is the human readable error in the locale of the request.
+	Message string `json:"message,omitempty"`
+}
+
+// ValidationError is returned by StructValidation when the request is invalid.
+// It matches ErrValidationFailed with errors.Is so callers that only care
+// about the outcome keep working.
+type ValidationError struct {
+	Fields []FieldError
 }
 
-func NewValidatorWrapper(v *validator.Validate) *validatorWrapper {
-	return &validatorWrapper{
-		validator: v,
-		mu:        sync.Mutex{},
-		csvFile:   "validation_errors.csv",
+func (e *ValidationError) Error() string {
+	names := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		names = append(names, f.Field+":"+f.Tag)
 	}
+	return fmt.Sprintf("%s: %s", ErrValidationFailed, strings.Join(names, ", "))
 }
 
-func (v *validatorWrapper) StructValidation(ctx context.Context, req any) error {
-	err := v.validator.StructCtx(ctx, req)
-	if err != nil {
-		var validationErrs validator.ValidationErrors
-		if errors.As(err, &validationErrs) {
-			// Log validation errors to CSV
-			err := v.logValidationErrors(validationErrs)
-			if err != nil {
-				return err
-			}
-			return ErrValidationFailed
-		}
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidationFailed
+}
+
+func newValidationError(vErr validator.ValidationErrors, trans ut.Translator) *ValidationError {
+	fields := make([]FieldError, 0, len(vErr))
+	for _, fieldErr := range vErr {
+		fields = append(fields, FieldError{
+			Field:   fieldErr.Field(),
+			Tag:     fieldErr.Tag(),
+			Param:   fieldErr.Param(),
+			Value:   fieldErr.Value(),
+			Message: fieldErr.Translate(trans),
+		})
 	}
-	return err
+	return &ValidationError{Fields: fields}
 }
 
-func (v *validatorWrapper) logValidationErrors(vErr validator.ValidationErrors) error {
-	v.mu.Lock()
-	defer v.mu.Unlock()
+// FailureRecorder counts validation failures, e.g. as metrics.
+type FailureRecorder interface {
+	RecordValidationFailure(structName, field, tag string)
+}
 
-	// Check if file exists to determine if we need to write headers
-	fileExists := true
-	_, err := os.Stat(v.csvFile)
-	if os.IsNotExist(err) {
-		fileExists = false
+type validatorWrapper struct {
+	validator   *validator.Validate
+	sink        ErrorSink
+	logger      *slog.Logger
+	recorders   []FailureRecorder
+	tracer      trace.Tracer
+	translator  *ut.UniversalTranslator
+	onSinkError func(error)
+	sinkFailed  atomic.Uint64
+}
+
+// Option customizes NewValidatorWrapper.
+type Option func(*validatorWrapper)
+
+// WithSinkErrorHandler sets the callback that receives sink write failures.
+// By default they are logged.
+func WithSinkErrorHandler(fn func(error)) Option {
+	return func(v *validatorWrapper) {
+		v.onSinkError = fn
 	}
+}
 
-	// Open CSV file in append mode
-	file, err := os.OpenFile(v.csvFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+// WithFailureRecorder reports every failing field to r.
+func WithFailureRecorder(r FailureRecorder) Option {
+	return func(v *validatorWrapper) {
+		v.recorders = append(v.recorders, r)
+	}
+}
+
+// WithTracerProvider creates a span for every StructValidation call.
+// Without it tracing is a no-op.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(v *validatorWrapper) {
+		v.tracer = tp.Tracer(instrumentationName)
+	}
+}
+
+// NewValidatorWrapper wraps v and reports every validation failure to sink.
+// Field errors carry a message in the locale set with ContextWithLocale.
+func NewValidatorWrapper(v *validator.Validate, sink ErrorSink, logger *slog.Logger, opts ...Option) *validatorWrapper {
+	// report fields by their json name, which is what clients actually send
+	v.RegisterTagNameFunc(jsontag.Name)
+	// custom tags used by the handler requests
+	if err := v.RegisterValidation(thaiCIDTag, validateThaiCID); err != nil {
+		panic(err)
+	}
+	translator, err := newTranslator(v)
 	if err != nil {
-		fmt.Printf("Error opening CSV file: %v\n", err)
-		return err
+		panic(err)
+	}
+	vw := &validatorWrapper{
+		validator:  v,
+		sink:       sink,
+		logger:     logger,
+		tracer:     noop.NewTracerProvider().Tracer(instrumentationName),
+		translator: translator,
 	}
-	defer file.Close()
+	vw.onSinkError = vw.logSinkError
+	for _, opt := range opts {
+		opt(vw)
+	}
+	return vw
+}
 
-	writer := csv.NewWriter(file)
-	defer writer.Flush()
+func (v *validatorWrapper) StructValidation(ctx context.Context, req any) error {
+	ctx, span := v.tracer.Start(ctx, "validatorwrapper.StructValidation",
+		trace.WithAttributes(attribute.String("validation.request", fmt.Sprintf("%T", req))),
+	)
+	defer span.End()
+
+	err := v.validator.StructCtx(ctx, req)
+	if err != nil {
+		var validationErrs validator.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			span.SetAttributes(attribute.Int("validation.field_errors", len(validationErrs)))
+			v.recordFailures(validationErrs)
 
-	// Write header if file is new
-	if !fileExists {
-		header := []string{"timestamp", "struct_and_field_name", "error_tag"}
-		if err := writer.Write(header); err != nil {
-			fmt.Printf("Error writing CSV header: %v\n", err)
-			return err
+			// Report validation errors to the sink. A failing sink must not
+			// turn bad input into an internal error, so it is reported aside.
+			if err := v.sink.Write(ctx, newErrorRecords(ctx, req, validationErrs)); err != nil {
+				v.sinkFailed.Add(1)
+				v.onSinkError(err)
+			}
+			trans, _ := v.translator.GetTranslator(localeFromContext(ctx))
+			return newValidationError(validationErrs, trans)
 		}
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "validation could not run")
 	}
+	return err
+}
 
-	timestamp := time.Now().Format(time.RFC3339)
-
-	// Collect all rows
-	rows := make([][]string, 0, len(vErr))
+func (v *validatorWrapper) recordFailures(vErr validator.ValidationErrors) {
 	for _, fieldErr := range vErr {
-		row := []string{
-			timestamp,
-			fieldErr.StructNamespace(),
-			fieldErr.Tag(),
+		// "FavoriteNumRequest.UserID" -> "FavoriteNumRequest", "UserID"
+		structName, field, _ := strings.Cut(fieldErr.StructNamespace(), ".")
+		for _, r := range v.recorders {
+			r.RecordValidationFailure(structName, field, fieldErr.Tag())
 		}
-		rows = append(rows, row)
 	}
+}
 
-	// Write all rows at once
-	if err := writer.WriteAll(rows); err != nil {
-		fmt.Printf("Error writing CSV rows: %v\n", err)
-		return err
-	}
-	return nil
+// SinkFailures returns how many times the sink failed to record errors.
+func (v *validatorWrapper) SinkFailures() uint64 {
+	return v.sinkFailed.Load()
+}
+
+func (v *validatorWrapper) logSinkError(err error) {
+	v.logger.Error("failed to record validation errors", "error", err)
 }
@@ -0,0 +1,73 @@
+package validatorwrapper
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+)
+
+type sampleRequest struct {
+	Name string `json:"name" validate:"required,min=3"`
+}
+
+func TestStructValidation_SinkFailureStillReportsValidationError(t *testing.T) {
+	sinkErr := errors.New("no space left on device")
+	var reported []error
+	vw := NewValidatorWrapper(
+		validator.New(validator.WithRequiredStructEnabled()),
+		&recordingSink{err: sinkErr},
+		slog.New(slog.DiscardHandler),
+		WithSinkErrorHandler(func(err error) { reported = append(reported, err) }),
+	)
+
+	err := vw.StructValidation(context.Background(), &sampleRequest{Name: "ab"})
+
+	assert.ErrorIs(t, err, ErrValidationFailed)
+	assert.NotErrorIs(t, err, sinkErr)
+	assert.Equal(t, []error{sinkErr}, reported)
+	assert.Equal(t, uint64(1), vw.SinkFailures())
+}
+
+func TestStructValidation_Valid(t *testing.T) {
+	sink := &recordingSink{}
+	vw := NewValidatorWrapper(validator.New(validator.WithRequiredStructEnabled()), sink, slog.New(slog.DiscardHandler))
+
+	err := vw.StructValidation(context.Background(), &sampleRequest{Name: "abc"})
+
+	assert.NoError(t, err)
+	assert.Zero(t, sink.count())
+}
+
+func TestStructValidation_FieldErrors(t *testing.T) {
+	sink := &recordingSink{}
+	vw := NewValidatorWrapper(validator.New(validator.WithRequiredStructEnabled()), sink, slog.New(slog.DiscardHandler))
+
+	err := vw.StructValidation(context.Background(), &sampleRequest{Name: "ab"})
+
+	var vErr *ValidationError
+	assert.ErrorAs(t, err, &vErr)
+	assert.Equal(t, []FieldError{{Field: "name", Tag: "min", Param: "3", Value: "ab", Message: "name must be at least 3 characters in length"}}, vErr.Fields)
+	assert.Equal(t, 1, sink.count())
+	assert.Equal(t, "sampleRequest.Name", sink.batches[0][0].StructNamespace)
+}
+
+func TestStructValidation_LogsSinkFailureByDefault(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	vw := NewValidatorWrapper(
+		validator.New(validator.WithRequiredStructEnabled()),
+		&recordingSink{err: errors.New("no space left on device")},
+		logger,
+	)
+
+	err := vw.StructValidation(context.Background(), &sampleRequest{})
+
+	assert.ErrorIs(t, err, ErrValidationFailed)
+	assert.Contains(t, buf.String(), `"msg":"failed to record validation errors"`)
+	assert.Contains(t, buf.String(), `"error":"no space left on device"`)
+}